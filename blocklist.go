@@ -0,0 +1,317 @@
+package registrantalert
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Blocklist is the set of blocked domain names in the ASCII (Punycode) form.
+// It is maintained incrementally from Registrant Alert results: added and discovered domains
+// are blocked, dropped domains are removed. The zero value is an empty blocklist ready to use.
+type Blocklist struct {
+	domains map[string]struct{}
+}
+
+// NewBlocklist creates Blocklist with the given domain names.
+func NewBlocklist(names ...string) (*Blocklist, error) {
+	b := &Blocklist{domains: make(map[string]struct{})}
+
+	if err := b.Add(names...); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Add blocks the domain names. Unicode names are converted to Punycode.
+func (b *Blocklist) Add(names ...string) error {
+	for _, name := range names {
+		ascii, err := toASCII(name)
+		if err != nil {
+			return err
+		}
+		if b.domains == nil {
+			b.domains = make(map[string]struct{})
+		}
+		b.domains[ascii] = struct{}{}
+	}
+
+	return nil
+}
+
+// Remove unblocks the domain names.
+func (b *Blocklist) Remove(names ...string) error {
+	for _, name := range names {
+		ascii, err := toASCII(name)
+		if err != nil {
+			return err
+		}
+		delete(b.domains, ascii)
+	}
+
+	return nil
+}
+
+// Contains reports whether the domain name is blocked.
+func (b *Blocklist) Contains(name string) bool {
+	ascii, err := toASCII(name)
+	if err != nil {
+		return false
+	}
+
+	_, ok := b.domains[ascii]
+	return ok
+}
+
+// Len returns the number of blocked domain names.
+func (b *Blocklist) Len() int {
+	return len(b.domains)
+}
+
+// Domains returns the sorted list of blocked domain names.
+func (b *Blocklist) Domains() []string {
+	domains := make([]string, 0, len(b.domains))
+	for domain := range b.domains {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+
+	return domains
+}
+
+// Apply updates the blocklist with the results: Added and Discovered domains are blocked,
+// Dropped domains are removed, other actions are ignored. The items are applied in order.
+// Items with malformed names are skipped and the first such error is returned.
+func (b *Blocklist) Apply(items []DomainItem) error {
+	var firstErr error
+
+	for _, item := range items {
+		var err error
+
+		switch item.Action {
+		case Added, Discovered:
+			err = b.Add(item.DomainName)
+		case Dropped:
+			err = b.Remove(item.DomainName)
+		}
+
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// HostsOptions configures the hosts file output.
+type HostsOptions struct {
+	// Address is the address the blocked domains resolve to. Default: 0.0.0.0.
+	Address string
+
+	// Comment is written at the beginning of the file. Every line is prefixed with "# ".
+	Comment string
+}
+
+// WriteHosts writes the blocklist in the hosts file format.
+func (b *Blocklist) WriteHosts(w io.Writer, opts HostsOptions) error {
+	address := opts.Address
+	if address == "" {
+		address = "0.0.0.0"
+	}
+
+	bw := bufio.NewWriter(w)
+
+	writeComment(bw, "# ", opts.Comment)
+	for _, domain := range b.Domains() {
+		bw.WriteString(address + " " + domain + "\n")
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("cannot write hosts: %w", err)
+	}
+
+	return nil
+}
+
+// ReadHosts loads the blocklist from the hosts file, e.g. the one written by WriteHosts.
+// All host names of every entry are added, the addresses are ignored.
+func ReadHosts(r io.Reader) (*Blocklist, error) {
+	b, _ := NewBlocklist()
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) < 2 {
+			continue
+		}
+
+		if err := b.Add(fields[1:]...); err != nil {
+			return nil, fmt.Errorf("cannot read hosts: line %d: %w", line, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read hosts: %w", err)
+	}
+
+	return b, nil
+}
+
+// RPZPolicy is the Response Policy Zone action applied to the blocked domains.
+type RPZPolicy string
+
+// List of RPZ policies.
+const (
+	RPZPolicyNXDomain RPZPolicy = "."
+	RPZPolicyNoData   RPZPolicy = "*."
+	RPZPolicyDrop     RPZPolicy = "rpz-drop."
+)
+
+var _ = []RPZPolicy{
+	RPZPolicyNXDomain,
+	RPZPolicyNoData,
+	RPZPolicyDrop,
+}
+
+// RPZOptions configures the Response Policy Zone output.
+type RPZOptions struct {
+	// Policy is the action applied to the blocked domains. Default: RPZPolicyNXDomain.
+	Policy RPZPolicy
+
+	// OmitSubdomains disables blocking the subdomains of the blocked domains.
+	OmitSubdomains bool
+
+	// TTL is the time to live of the zone records. Default: 5 minutes.
+	TTL time.Duration
+
+	// Serial is the SOA serial number. If it's zero then the current Unix time is used,
+	// so every generated zone supersedes the previous one.
+	Serial uint32
+
+	// PrimaryNS is the SOA primary name server. Default: localhost.
+	PrimaryNS string
+
+	// Hostmaster is the SOA mailbox of the zone administrator. Default: hostmaster.localhost.
+	Hostmaster string
+
+	// Comment is written at the beginning of the file. Every line is prefixed with "; ".
+	Comment string
+}
+
+// WriteRPZ writes the blocklist as the Response Policy Zone file.
+// The trigger names are relative to the zone origin.
+func (b *Blocklist) WriteRPZ(w io.Writer, opts RPZOptions) error {
+	if opts.TTL < 0 {
+		return &ArgError{"RPZOptions.TTL", "must not be negative."}
+	}
+
+	policy := opts.Policy
+	if policy == "" {
+		policy = RPZPolicyNXDomain
+	}
+
+	ttl := opts.TTL
+	if ttl == 0 {
+		ttl = 5 * time.Minute
+	}
+
+	serial := opts.Serial
+	if serial == 0 {
+		serial = uint32(time.Now().Unix())
+	}
+
+	primaryNS := fqdn(opts.PrimaryNS, "localhost.")
+	hostmaster := fqdn(opts.Hostmaster, "hostmaster.localhost.")
+
+	bw := bufio.NewWriter(w)
+
+	writeComment(bw, "; ", opts.Comment)
+
+	seconds := strconv.Itoa(int(ttl / time.Second))
+	bw.WriteString("$TTL " + seconds + "\n")
+	bw.WriteString("@ IN SOA " + primaryNS + " " + hostmaster + " (" +
+		strconv.FormatUint(uint64(serial), 10) + " 3600 600 86400 " + seconds + ")\n")
+	bw.WriteString("  IN NS " + primaryNS + "\n")
+
+	for _, domain := range b.Domains() {
+		bw.WriteString(domain + " CNAME " + string(policy) + "\n")
+		if !opts.OmitSubdomains {
+			bw.WriteString("*." + domain + " CNAME " + string(policy) + "\n")
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("cannot write rpz: %w", err)
+	}
+
+	return nil
+}
+
+// ReadRPZ loads the blocklist from the Response Policy Zone file, e.g. the one written by WriteRPZ.
+// Only the relative trigger names are loaded, wildcard triggers are folded into their domains.
+func ReadRPZ(r io.Reader) (*Blocklist, error) {
+	b, _ := NewBlocklist()
+
+	scanner := bufio.NewScanner(r)
+	parens := 0
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.IndexByte(text, ';'); i >= 0 {
+			text = text[:i]
+		}
+
+		// Skip the continuation lines of multi-line records like SOA.
+		inRecord := parens > 0
+		parens += strings.Count(text, "(") - strings.Count(text, ")")
+		if inRecord || text == "" || text[0] == ' ' || text[0] == '\t' || text[0] == '$' {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) < 3 || fields[0] == "@" || strings.HasSuffix(fields[0], ".") {
+			continue
+		}
+
+		if err := b.Add(strings.TrimPrefix(fields[0], "*.")); err != nil {
+			return nil, fmt.Errorf("cannot read rpz: line %d: %w", line, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read rpz: %w", err)
+	}
+
+	return b, nil
+}
+
+// writeComment writes every line of the comment with the prefix.
+func writeComment(w *bufio.Writer, prefix, comment string) {
+	if comment == "" {
+		return
+	}
+
+	for _, line := range strings.Split(comment, "\n") {
+		w.WriteString(prefix + line + "\n")
+	}
+}
+
+// fqdn returns the fully-qualified name with the trailing dot, or the default if the name is empty.
+func fqdn(name, def string) string {
+	if name == "" {
+		return def
+	}
+	if !strings.HasSuffix(name, ".") {
+		return name + "."
+	}
+	return name
+}
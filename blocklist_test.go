@@ -0,0 +1,127 @@
+package registrantalert
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestToASCII tests the Punycode conversion of domain names.
+func TestToASCII(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr string
+	}{
+		{"Example.COM.", "example.com", ""},
+		{"bücher.example", "xn--bcher-kva.example", ""},
+		{"München.de", "xn--mnchen-3ya.de", ""},
+		{"пример.рф", "xn--e1afmkfd.xn--p1ai", ""},
+		{"中国。cn", "xn--fiqs8s.cn", ""},
+		{"правительство.рф", "xn--80aealotwbjpid2k.xn--p1ai", ""},
+		{"Ｅｘａｍｐｌｅ．ＣＯＭ", "example.com", ""},
+		{"ｏﬃce.com", "office.com", ""},
+		{"bu\u0308cher.example", "", "invalid domain name \"bu\u0308cher.example\": is not in Unicode NFC form, the combining mark '\\u0308' must be composed"},
+		{"ｶﾞ.jp", "", `invalid domain name "ｶﾞ.jp": has a halfwidth character 'ｶ' that must be mapped to the fullwidth form`},
+		{"-bad.com", "", `invalid domain name "-bad.com": has a label starting or ending with a hyphen`},
+		{"a..com", "", `invalid domain name "a..com": has an empty label`},
+		{"bad!.com", "", `invalid domain name "bad!.com": has an invalid character '!'`},
+		{"", "", `invalid domain name "": is empty`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toASCII(tt.name)
+			checkErr(t, err, tt.wantErr)
+			if got != tt.want {
+				t.Errorf("toASCII() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestBlocklist tests the incremental blocklist generation.
+func TestBlocklist(t *testing.T) {
+	b, err := NewBlocklist("old.com", "dropped.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = b.Apply([]DomainItem{
+		{DomainName: "bücher.com", Action: Added},
+		{DomainName: "found.com", Action: Discovered},
+		{DomainName: "updated.com", Action: Updated},
+		{DomainName: "DROPPED.com", Action: Dropped},
+		{DomainName: "bad domain.com", Action: Added},
+	})
+	checkErr(t, err, `invalid domain name "bad domain.com": has an invalid character ' '`)
+
+	want := []string{"found.com", "old.com", "xn--bcher-kva.com"}
+	if !reflect.DeepEqual(b.Domains(), want) {
+		t.Errorf("Domains() = %v, want %v", b.Domains(), want)
+	}
+	if !b.Contains("Bücher.com") || b.Contains("dropped.com") {
+		t.Error("Contains() returned unexpected result")
+	}
+
+	var hosts bytes.Buffer
+	if err := b.WriteHosts(&hosts, HostsOptions{Comment: "Registrant Alert"}); err != nil {
+		t.Fatal(err)
+	}
+
+	const wantHosts = "# Registrant Alert\n0.0.0.0 found.com\n0.0.0.0 old.com\n0.0.0.0 xn--bcher-kva.com\n"
+	if hosts.String() != wantHosts {
+		t.Errorf("WriteHosts() = %q, want %q", hosts.String(), wantHosts)
+	}
+
+	fromHosts, err := ReadHosts(&hosts)
+	if err != nil || !reflect.DeepEqual(fromHosts.Domains(), want) {
+		t.Errorf("ReadHosts() = %v, %v, want %v", fromHosts, err, want)
+	}
+
+	var rpz bytes.Buffer
+	if err := b.WriteRPZ(&rpz, RPZOptions{Serial: 2022110101, Policy: RPZPolicyNoData}); err != nil {
+		t.Fatal(err)
+	}
+
+	const wantRPZ = `$TTL 300
+@ IN SOA localhost. hostmaster.localhost. (2022110101 3600 600 86400 300)
+  IN NS localhost.
+found.com CNAME *.
+*.found.com CNAME *.
+old.com CNAME *.
+*.old.com CNAME *.
+xn--bcher-kva.com CNAME *.
+*.xn--bcher-kva.com CNAME *.
+`
+	if rpz.String() != wantRPZ {
+		t.Errorf("WriteRPZ() = %v, want %v", rpz.String(), wantRPZ)
+	}
+
+	fromRPZ, err := ReadRPZ(strings.NewReader("$ORIGIN rpz.\n@ SOA ns. host. (\n 1 2 3\n 4 5 )\n" + rpz.String()))
+	if err != nil || !reflect.DeepEqual(fromRPZ.Domains(), want) {
+		t.Errorf("ReadRPZ() = %v, %v, want %v", fromRPZ, err, want)
+	}
+
+	err = b.WriteRPZ(&rpz, RPZOptions{TTL: -time.Minute})
+	checkErr(t, err, `invalid argument: "RPZOptions.TTL" must not be negative.`)
+}
+
+// TestBlocklistZeroValue tests that the zero value is usable.
+func TestBlocklistZeroValue(t *testing.T) {
+	var b Blocklist
+
+	if b.Contains("example.com") || b.Len() != 0 {
+		t.Error("zero value is not empty")
+	}
+	if err := b.Remove("example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Add("example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if !b.Contains("example.com") || b.Len() != 1 {
+		t.Errorf("Domains() = %v", b.Domains())
+	}
+}
@@ -0,0 +1,220 @@
+package registrantalert
+
+import (
+	"time"
+)
+
+// DateRange is the range of dates with both ends included.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// Period computes the date range relative to today's date (midnight in the calendar's time zone).
+type Period func(today time.Time) DateRange
+
+// Yesterday is the period of the previous day.
+func Yesterday(today time.Time) DateRange {
+	yesterday := today.AddDate(0, 0, -1)
+	return DateRange{yesterday, yesterday}
+}
+
+// Today is the period of the current day.
+func Today(today time.Time) DateRange {
+	return DateRange{today, today}
+}
+
+// LastFullWeek is the period from Monday to Sunday of the previous week.
+func LastFullWeek(today time.Time) DateRange {
+	monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	return DateRange{monday.AddDate(0, 0, -7), monday.AddDate(0, 0, -1)}
+}
+
+// MonthToDate is the period from the first day of the current month to today.
+func MonthToDate(today time.Time) DateRange {
+	return DateRange{today.AddDate(0, 0, 1-today.Day()), today}
+}
+
+// LastFullMonth is the period of the previous calendar month.
+func LastFullMonth(today time.Time) DateRange {
+	first := today.AddDate(0, 0, 1-today.Day())
+	return DateRange{first.AddDate(0, -1, 0), first.AddDate(0, 0, -1)}
+}
+
+// LastDays returns the period of n days ending today.
+func LastDays(n int) Period {
+	return func(today time.Time) DateRange {
+		return DateRange{today.AddDate(0, 0, 1-n), today}
+	}
+}
+
+var _ = []Period{
+	Yesterday,
+	Today,
+	LastFullWeek,
+	MonthToDate,
+	LastFullMonth,
+	LastDays(7),
+}
+
+// Calendar evaluates relative dates in a time zone against a clock.
+// The zero value uses UTC and the system clock.
+//
+// The options created by Calendar are evaluated each time they are applied to a request,
+// so they can be used as client-wide defaults of long-running programs.
+type Calendar struct {
+	// Location is the time zone the dates are computed in.
+	// If it's nil then UTC is used.
+	Location *time.Location
+
+	// Now returns the current time.
+	// If it's nil then time.Now is used.
+	Now func() time.Time
+}
+
+// DefaultCalendar is the calendar used by the package-level relative options.
+var DefaultCalendar = Calendar{}
+
+// now returns the current time in the calendar's time zone.
+func (c Calendar) now() time.Time {
+	now := time.Now
+	if c.Now != nil {
+		now = c.Now
+	}
+
+	loc := time.UTC
+	if c.Location != nil {
+		loc = c.Location
+	}
+
+	return now().In(loc)
+}
+
+// Today returns the midnight of the current day.
+func (c Calendar) Today() time.Time {
+	now := c.now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
+
+// Range evaluates the period against the current date.
+func (c Calendar) Range(period Period) DateRange {
+	return period(c.Today())
+}
+
+// OptionSinceLast results in search through activities discovered during the last given duration.
+func (c Calendar) OptionSinceLast(d time.Duration) Option {
+	return func(v *registrantAlertRequest) {
+		OptionSinceDate(c.now().Add(-d))(v)
+	}
+}
+
+// OptionSinceStartOf results in search through activities discovered since the first day of the period.
+func (c Calendar) OptionSinceStartOf(period Period) Option {
+	return func(v *registrantAlertRequest) {
+		OptionSinceDate(c.Range(period).From)(v)
+	}
+}
+
+// OptionCreatedWithin searches through domains created between from and to ago,
+// e.g. OptionCreatedWithin(30*24*time.Hour, 0) covers the last 30 days.
+func (c Calendar) OptionCreatedWithin(from, to time.Duration) Option {
+	return func(v *registrantAlertRequest) {
+		now := c.now()
+		OptionCreatedDateFrom(now.Add(-from))(v)
+		OptionCreatedDateTo(now.Add(-to))(v)
+	}
+}
+
+// OptionUpdatedWithin searches through domains updated between from and to ago.
+func (c Calendar) OptionUpdatedWithin(from, to time.Duration) Option {
+	return func(v *registrantAlertRequest) {
+		now := c.now()
+		OptionUpdatedDateFrom(now.Add(-from))(v)
+		OptionUpdatedDateTo(now.Add(-to))(v)
+	}
+}
+
+// OptionExpiredWithin searches through domains expired between from and to ago.
+// Negative durations point to the future.
+func (c Calendar) OptionExpiredWithin(from, to time.Duration) Option {
+	return func(v *registrantAlertRequest) {
+		now := c.now()
+		OptionExpiredDateFrom(now.Add(-from))(v)
+		OptionExpiredDateTo(now.Add(-to))(v)
+	}
+}
+
+// OptionCreatedDuring searches through domains created during the period.
+func (c Calendar) OptionCreatedDuring(period Period) Option {
+	return func(v *registrantAlertRequest) {
+		r := c.Range(period)
+		OptionCreatedDateFrom(r.From)(v)
+		OptionCreatedDateTo(r.To)(v)
+	}
+}
+
+// OptionUpdatedDuring searches through domains updated during the period.
+func (c Calendar) OptionUpdatedDuring(period Period) Option {
+	return func(v *registrantAlertRequest) {
+		r := c.Range(period)
+		OptionUpdatedDateFrom(r.From)(v)
+		OptionUpdatedDateTo(r.To)(v)
+	}
+}
+
+// OptionExpiredDuring searches through domains expired during the period.
+func (c Calendar) OptionExpiredDuring(period Period) Option {
+	return func(v *registrantAlertRequest) {
+		r := c.Range(period)
+		OptionExpiredDateFrom(r.From)(v)
+		OptionExpiredDateTo(r.To)(v)
+	}
+}
+
+// OptionSinceLast results in search through activities discovered during the last given duration.
+// The date is computed with DefaultCalendar.
+func OptionSinceLast(d time.Duration) Option {
+	return DefaultCalendar.OptionSinceLast(d)
+}
+
+// OptionSinceStartOf results in search through activities discovered since the first day of the period.
+// The date is computed with DefaultCalendar.
+func OptionSinceStartOf(period Period) Option {
+	return DefaultCalendar.OptionSinceStartOf(period)
+}
+
+// OptionCreatedWithin searches through domains created between from and to ago.
+// The dates are computed with DefaultCalendar.
+func OptionCreatedWithin(from, to time.Duration) Option {
+	return DefaultCalendar.OptionCreatedWithin(from, to)
+}
+
+// OptionUpdatedWithin searches through domains updated between from and to ago.
+// The dates are computed with DefaultCalendar.
+func OptionUpdatedWithin(from, to time.Duration) Option {
+	return DefaultCalendar.OptionUpdatedWithin(from, to)
+}
+
+// OptionExpiredWithin searches through domains expired between from and to ago.
+// The dates are computed with DefaultCalendar.
+func OptionExpiredWithin(from, to time.Duration) Option {
+	return DefaultCalendar.OptionExpiredWithin(from, to)
+}
+
+// OptionCreatedDuring searches through domains created during the period.
+// The dates are computed with DefaultCalendar.
+func OptionCreatedDuring(period Period) Option {
+	return DefaultCalendar.OptionCreatedDuring(period)
+}
+
+// OptionUpdatedDuring searches through domains updated during the period.
+// The dates are computed with DefaultCalendar.
+func OptionUpdatedDuring(period Period) Option {
+	return DefaultCalendar.OptionUpdatedDuring(period)
+}
+
+// OptionExpiredDuring searches through domains expired during the period.
+// The dates are computed with DefaultCalendar.
+func OptionExpiredDuring(period Period) Option {
+	return DefaultCalendar.OptionExpiredDuring(period)
+}
@@ -0,0 +1,79 @@
+package registrantalert
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestCalendarPeriods tests the Period functions.
+func TestCalendarPeriods(t *testing.T) {
+	// Wednesday, 2022-11-02 01:30 in UTC is still 2022-11-01 in New York.
+	now := time.Date(2022, 11, 2, 1, 30, 0, 0, time.UTC)
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	tests := []struct {
+		name     string
+		location *time.Location
+		period   Period
+		want     [2]string
+	}{
+		{"today", nil, Today, [2]string{"2022-11-02", "2022-11-02"}},
+		{"yesterday", nil, Yesterday, [2]string{"2022-11-01", "2022-11-01"}},
+		{"yesterday in New York", newYork, Yesterday, [2]string{"2022-10-31", "2022-10-31"}},
+		{"last full week", nil, LastFullWeek, [2]string{"2022-10-24", "2022-10-30"}},
+		{"month to date", nil, MonthToDate, [2]string{"2022-11-01", "2022-11-02"}},
+		{"month to date in New York", newYork, MonthToDate, [2]string{"2022-11-01", "2022-11-01"}},
+		{"last full month", nil, LastFullMonth, [2]string{"2022-10-01", "2022-10-31"}},
+		{"last 7 days", nil, LastDays(7), [2]string{"2022-10-27", "2022-11-02"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Calendar{Location: tt.location, Now: func() time.Time { return now }}
+
+			r := c.Range(tt.period)
+			got := [2]string{r.From.Format(dateFormat), r.To.Format(dateFormat)}
+			if got != tt.want {
+				t.Errorf("Range() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCalendarOptions tests the relative Options.
+func TestCalendarOptions(t *testing.T) {
+	now := time.Date(2022, 11, 2, 12, 0, 0, 0, time.UTC)
+	c := Calendar{Now: func() time.Time { return now }}
+
+	var request registrantAlertRequest
+
+	c.OptionSinceLast(7 * 24 * time.Hour)(&request)
+	c.OptionCreatedWithin(30*24*time.Hour, 24*time.Hour)(&request)
+	c.OptionUpdatedDuring(LastFullWeek)(&request)
+	c.OptionExpiredWithin(0, -365*24*time.Hour)(&request)
+
+	want := registrantAlertRequest{
+		SinceDate:       "2022-10-26",
+		CreatedDateFrom: "2022-10-03",
+		CreatedDateTo:   "2022-11-01",
+		UpdatedDateFrom: "2022-10-24",
+		UpdatedDateTo:   "2022-10-30",
+		ExpiredDateFrom: "2022-11-02",
+		ExpiredDateTo:   "2023-11-02",
+	}
+	if !reflect.DeepEqual(request, want) {
+		t.Errorf("request = %+v, want %+v", request, want)
+	}
+
+	// The options are evaluated when applied, not when created.
+	option := c.OptionSinceStartOf(MonthToDate)
+	now = now.AddDate(0, 1, 0)
+	option(&request)
+	if request.SinceDate != "2022-12-01" {
+		t.Errorf("SinceDate = %v, want 2022-12-01", request.SinceDate)
+	}
+}
@@ -0,0 +1,346 @@
+package registrantalert
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Platform limits of the chat messages.
+const (
+	slackMaxBlocks       = 50
+	slackMaxSectionText  = 3000
+	slackMaxHeaderText   = 150
+	slackMaxPayloadBytes = 40000
+	teamsMaxPayloadBytes = 28000
+)
+
+// defaultChatMaxDomains is the number of domains listed per action group when none is specified.
+const defaultChatMaxDomains = 20
+
+// ChatOptions configures the chat message formatting.
+type ChatOptions struct {
+	// Title is the message title. Default: "Registrant Alert digest".
+	Title string
+
+	// MaxDomains is the maximum number of domains listed per action group,
+	// the rest is summarised as a count. Default: 20.
+	MaxDomains int
+
+	// DomainURL returns the link of the domain.
+	// If it's nil then the WHOIS lookup page on whoisxmlapi.com is used.
+	DomainURL func(domainName string) string
+
+	// MaxPayloadBytes is the maximum size of a single message.
+	// Default: 40000 for Slack and 28000 for Microsoft Teams.
+	MaxPayloadBytes int
+}
+
+// validate checks the limits of the options.
+func (o ChatOptions) validate() error {
+	if o.MaxDomains < 0 {
+		return &ArgError{"ChatOptions.MaxDomains", "must not be negative."}
+	}
+	if o.MaxPayloadBytes < 0 {
+		return &ArgError{"ChatOptions.MaxPayloadBytes", "must not be negative."}
+	}
+	return nil
+}
+
+// digest groups the results for the chat message.
+func (o ChatOptions) digest(results []SearchResult) *Digest {
+	maxDomains := o.MaxDomains
+	if maxDomains == 0 {
+		maxDomains = defaultChatMaxDomains
+	}
+
+	return NewDigest(results, DigestOptions{
+		Title:      o.Title,
+		DomainURL:  o.DomainURL,
+		MaxDomains: maxDomains,
+	})
+}
+
+// chunkElements splits the elements into payloads within the count and size limits.
+// The wrap function builds the payload of the chunk.
+func chunkElements(elements []interface{}, maxCount, maxBytes int, wrap func(chunk []interface{}) interface{}) ([][]byte, error) {
+	empty, err := json.Marshal(wrap(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	var payloads [][]byte
+	var chunk []interface{}
+	size := len(empty)
+
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+
+		payload, err := json.Marshal(wrap(chunk))
+		if err != nil {
+			return err
+		}
+
+		payloads = append(payloads, payload)
+		chunk = nil
+		size = len(empty)
+
+		return nil
+	}
+
+	for _, element := range elements {
+		b, err := json.Marshal(element)
+		if err != nil {
+			return nil, err
+		}
+
+		// One more byte for the comma between the elements.
+		if len(chunk) > 0 && (len(chunk) >= maxCount || size+len(b)+1 > maxBytes) {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+
+		chunk = append(chunk, element)
+		size += len(b) + 1
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return payloads, nil
+}
+
+// chatLines returns the lines of the action group: the domains and the summary of the omitted ones.
+func chatLines(group DigestGroup, formatDomain func(DigestDomain) string) []string {
+	lines := make([]string, 0, len(group.Domains)+1)
+
+	for _, domain := range group.Domains {
+		line := formatDomain(domain)
+		if domain.Date != "" {
+			line += " (" + domain.Date + ")"
+		}
+		lines = append(lines, line)
+	}
+
+	if group.Omitted > 0 {
+		lines = append(lines, "... and "+strconv.Itoa(group.Omitted)+" more")
+	}
+
+	return lines
+}
+
+// splitText joins the lines into texts not longer than maxLen.
+func splitText(header string, lines []string, maxLen int) []string {
+	var texts []string
+
+	text := header
+	for _, line := range lines {
+		if len(text)+len(line)+1 > maxLen && text != "" {
+			texts = append(texts, text)
+			text = ""
+		}
+		if text != "" {
+			text += "\n"
+		}
+		text += line
+	}
+
+	return append(texts, text)
+}
+
+// truncate shortens the string to maxLen bytes keeping it valid UTF-8.
+func truncate(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+
+	s = s[:maxLen-3]
+	for !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+
+	return s + "..."
+}
+
+// slackEscape escapes the control characters of Slack mrkdwn.
+var slackEscape = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// slackURLEscape escapes the link URL of Slack mrkdwn. The pipe separates the URL from the link text.
+var slackURLEscape = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "|", "%7C")
+
+// slackText is the Slack text object.
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// slackBlock is the Slack layout block.
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+// slackMessage is the Slack incoming webhook payload.
+type slackMessage struct {
+	Text   string        `json:"text"`
+	Blocks []interface{} `json:"blocks"`
+}
+
+// SlackMessages formats the results as Slack Block Kit messages for incoming webhooks.
+// The blocks are split into several messages to fit the Slack limits.
+func SlackMessages(results []SearchResult, opts ChatOptions) ([][]byte, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	d := opts.digest(results)
+
+	maxBytes := opts.MaxPayloadBytes
+	if maxBytes == 0 {
+		maxBytes = slackMaxPayloadBytes
+	}
+
+	formatDomain := func(domain DigestDomain) string {
+		if domain.URL == "" {
+			return "• " + slackEscape.Replace(domain.Name)
+		}
+		return "• <" + slackURLEscape.Replace(domain.URL) + "|" + slackEscape.Replace(domain.Name) + ">"
+	}
+
+	blocks := []interface{}{
+		slackBlock{Type: "header", Text: &slackText{"plain_text", truncate(d.Title, slackMaxHeaderText)}},
+	}
+
+	for _, section := range d.Sections {
+		blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{"mrkdwn",
+			truncate("*"+slackEscape.Replace(section.Search)+"*: "+strconv.Itoa(section.Total)+" domains", slackMaxSectionText)}})
+
+		if section.Terms != "" && section.Terms != section.Search {
+			blocks = append(blocks, slackBlock{Type: "context", Elements: []slackText{
+				{"mrkdwn", truncate("Search terms: "+slackEscape.Replace(section.Terms), slackMaxSectionText)},
+			}})
+		}
+
+		for _, group := range section.Groups {
+			header := "*" + string(group.Action) + "* (" + strconv.Itoa(group.Count) + ")"
+			for _, text := range splitText(header, chatLines(group, formatDomain), slackMaxSectionText) {
+				blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{"mrkdwn", text}})
+			}
+		}
+	}
+
+	fallback := d.Title + ": " + strconv.Itoa(d.Total) + " domains"
+
+	return chunkElements(blocks, slackMaxBlocks, maxBytes, func(chunk []interface{}) interface{} {
+		return slackMessage{Text: fallback, Blocks: chunk}
+	})
+}
+
+// teamsTextBlock is the Adaptive Card text block.
+type teamsTextBlock struct {
+	Type     string `json:"type"`
+	Text     string `json:"text"`
+	Wrap     bool   `json:"wrap"`
+	Size     string `json:"size,omitempty"`
+	Weight   string `json:"weight,omitempty"`
+	IsSubtle bool   `json:"isSubtle,omitempty"`
+}
+
+// teamsCard is the Adaptive Card.
+type teamsCard struct {
+	Schema  string        `json:"$schema"`
+	Type    string        `json:"type"`
+	Version string        `json:"version"`
+	Body    []interface{} `json:"body"`
+}
+
+// teamsAttachment is the attachment of the Teams message.
+type teamsAttachment struct {
+	ContentType string    `json:"contentType"`
+	Content     teamsCard `json:"content"`
+}
+
+// teamsMessage is the Microsoft Teams incoming webhook payload.
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+// teamsEscape escapes the markdown control characters of the domain names.
+var teamsEscape = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`, "*", `\*`, "_", `\_`)
+
+// TeamsMessages formats the results as Microsoft Teams Adaptive Card messages for incoming webhooks.
+// The card body is split into several messages to fit the Teams payload limit.
+func TeamsMessages(results []SearchResult, opts ChatOptions) ([][]byte, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	d := opts.digest(results)
+
+	maxBytes := opts.MaxPayloadBytes
+	if maxBytes == 0 {
+		maxBytes = teamsMaxPayloadBytes
+	}
+
+	formatDomain := func(domain DigestDomain) string {
+		if domain.URL == "" {
+			return "- " + teamsEscape.Replace(domain.Name)
+		}
+		return "- [" + teamsEscape.Replace(domain.Name) + "](" + domain.URL + ")"
+	}
+
+	body := []interface{}{
+		teamsTextBlock{Type: "TextBlock", Text: d.Title, Wrap: true, Size: "Large", Weight: "Bolder"},
+	}
+
+	for _, section := range d.Sections {
+		body = append(body, teamsTextBlock{Type: "TextBlock", Wrap: true, Size: "Medium", Weight: "Bolder",
+			Text: section.Search + ": " + strconv.Itoa(section.Total) + " domains"})
+
+		if section.Terms != "" && section.Terms != section.Search {
+			body = append(body, teamsTextBlock{Type: "TextBlock", Wrap: true, IsSubtle: true,
+				Text: "Search terms: " + section.Terms})
+		}
+
+		for _, group := range section.Groups {
+			header := "**" + string(group.Action) + "** (" + strconv.Itoa(group.Count) + ")"
+			for _, text := range splitText(header, chatLines(group, formatDomain), maxBytes/2) {
+				body = append(body, teamsTextBlock{Type: "TextBlock", Wrap: true, Text: text})
+			}
+		}
+	}
+
+	return chunkElements(body, len(body), maxBytes, func(chunk []interface{}) interface{} {
+		return teamsMessage{
+			Type: "message",
+			Attachments: []teamsAttachment{{
+				ContentType: "application/vnd.microsoft.card.adaptive",
+				Content: teamsCard{
+					Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+					Type:    "AdaptiveCard",
+					Version: "1.4",
+					Body:    chunk,
+				},
+			}},
+		}
+	})
+}
+
+// PostMessages posts every message to the webhook endpoints in order.
+// It stops at the first message that cannot be delivered.
+func (n *WebhookNotifier) PostMessages(ctx context.Context, messages [][]byte) error {
+	for _, message := range messages {
+		if err := n.Post(ctx, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package registrantalert
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// manyDomainsResult returns the search result with n added domains.
+func manyDomainsResult(n int) SearchResult {
+	items := make([]DomainItem, n)
+	for i := range items {
+		items[i] = DomainItem{
+			DomainName: "domain" + strconv.Itoa(i) + ".com",
+			Action:     Added,
+			Date:       Time(time.Date(2022, 10, 30, 0, 0, 0, 0, time.UTC)),
+		}
+	}
+
+	return SearchResult{
+		Search:   Search{Name: "many <domains>"},
+		Response: &RegistrantAlertResponse{DomainsList: items, DomainsCount: n},
+	}
+}
+
+// TestSlackMessages tests the Slack Block Kit formatting.
+func TestSlackMessages(t *testing.T) {
+	messages, err := SlackMessages([]SearchResult{manyDomainsResult(30)}, ChatOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("got %d messages, want 1", len(messages))
+	}
+
+	var msg struct {
+		Text   string       `json:"text"`
+		Blocks []slackBlock `json:"blocks"`
+	}
+	if err := json.Unmarshal(messages[0], &msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Text != "Registrant Alert digest: 30 domains" {
+		t.Errorf("text = %q", msg.Text)
+	}
+
+	var text string
+	for _, block := range msg.Blocks {
+		if block.Text != nil {
+			text += block.Text.Text + "\n"
+		}
+	}
+	for _, want := range []string{
+		`*many &lt;domains&gt;*: 30 domains`,
+		`• <https://whois.whoisxmlapi.com/lookup?q=domain0.com|domain0.com> (2022-10-30)`,
+		`... and 10 more`,
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("message does not contain %s", want)
+		}
+	}
+
+	// The custom links are escaped.
+	messages, err = SlackMessages([]SearchResult{manyDomainsResult(1)}, ChatOptions{
+		DomainURL: func(domainName string) string { return "https://example.com/?q=" + domainName + "&v=a|b<c>" },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(messages[0], &msg); err != nil {
+		t.Fatal(err)
+	}
+	if want := `<https://example.com/?q=domain0.com&amp;v=a%7Cb&lt;c&gt;|domain0.com>`; !strings.Contains(msg.Blocks[2].Text.Text, want) {
+		t.Errorf("message %s does not contain %s", msg.Blocks[2].Text.Text, want)
+	}
+
+	_, err = SlackMessages(nil, ChatOptions{MaxPayloadBytes: -1})
+	checkErr(t, err, `invalid argument: "ChatOptions.MaxPayloadBytes" must not be negative.`)
+
+	_, err = TeamsMessages(nil, ChatOptions{MaxDomains: -1})
+	checkErr(t, err, `invalid argument: "ChatOptions.MaxDomains" must not be negative.`)
+
+	// Many domains are split into several section blocks and messages.
+	messages, err = SlackMessages([]SearchResult{manyDomainsResult(3000)}, ChatOptions{MaxDomains: 3000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) < 2 {
+		t.Fatalf("got %d messages, want several", len(messages))
+	}
+
+	for _, message := range messages {
+		var msg struct {
+			Blocks []slackBlock `json:"blocks"`
+		}
+		if err := json.Unmarshal(message, &msg); err != nil {
+			t.Fatal(err)
+		}
+		if len(msg.Blocks) > slackMaxBlocks || len(message) > slackMaxPayloadBytes {
+			t.Errorf("message has %d blocks and %d bytes", len(msg.Blocks), len(message))
+		}
+		for _, block := range msg.Blocks {
+			if block.Text != nil && len(block.Text.Text) > slackMaxSectionText {
+				t.Errorf("block text has %d bytes", len(block.Text.Text))
+			}
+		}
+	}
+}
+
+// TestTeamsMessages tests the Microsoft Teams Adaptive Card formatting and delivery.
+func TestTeamsMessages(t *testing.T) {
+	messages, err := TeamsMessages([]SearchResult{manyDomainsResult(1000)}, ChatOptions{MaxDomains: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) < 2 {
+		t.Fatalf("got %d messages, want several", len(messages))
+	}
+
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		received = append(received, string(body))
+	}))
+	defer server.Close()
+
+	notifier, err := NewWebhookNotifier(WebhookParams{URLs: []string{server.URL}, HTTPClient: server.Client()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := notifier.PostMessages(context.Background(), messages); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(received) != len(messages) {
+		t.Fatalf("received %d messages, want %d", len(received), len(messages))
+	}
+
+	for _, message := range received {
+		if len(message) > teamsMaxPayloadBytes {
+			t.Errorf("message has %d bytes", len(message))
+		}
+
+		var msg teamsMessage
+		if err := json.Unmarshal([]byte(message), &msg); err != nil {
+			t.Fatal(err)
+		}
+		if msg.Type != "message" || msg.Attachments[0].Content.Type != "AdaptiveCard" {
+			t.Errorf("message = %+v", msg)
+		}
+	}
+
+	if !strings.Contains(received[0], `- [domain0.com](https://whois.whoisxmlapi.com/lookup?q=domain0.com) (2022-10-30)`) {
+		t.Errorf("first message = %s", received[0])
+	}
+}
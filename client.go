@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 )
 
 const (
@@ -27,6 +28,18 @@ type ClientParams struct {
 
 	// RegistrantAlertBaseURL is the endpoint for 'Registrant Alert API' service
 	RegistrantAlertBaseURL *url.URL
+
+	// DefaultOptions are applied to every request of the client.
+	// Options are applied in the following order, the later ones take precedence:
+	//  1. the API defaults: preview mode, Punycode enabled, JSON output;
+	//  2. DefaultOptions in the given order;
+	//  3. the per-call options in the given order;
+	//  4. OptionResponseFormat(JSON) for the Preview and Purchase methods, as they parse JSON only.
+	DefaultOptions []Option
+
+	// Calendar is the clock the request dates are checked against, e.g. that SinceDate is not in the future.
+	// If it's nil then DefaultCalendar is used.
+	Calendar *Calendar
 }
 
 // NewBasicClient creates Client with recommended parameters.
@@ -52,9 +65,11 @@ func NewClient(apiKey string, params ClientParams) *Client {
 	}
 
 	client := &Client{
-		client:    httpClient,
-		userAgent: userAgent,
-		apiKey:    apiKey,
+		client:         httpClient,
+		userAgent:      userAgent,
+		apiKey:         apiKey,
+		defaultOptions: append([]Option(nil), params.DefaultOptions...),
+		calendar:       params.Calendar,
 	}
 
 	client.RegistrantAlert = &registrantAlertServiceOp{client: client, baseURL: apiBaseURL}
@@ -62,6 +77,42 @@ func NewClient(apiKey string, params ClientParams) *Client {
 	return client
 }
 
+// With returns a child client sharing the configuration of c with opts appended to its default options.
+// The parent client is not modified. The default options are applied by the built-in RegistrantAlert
+// service only: if c.RegistrantAlert is replaced with another implementation, the child shares it
+// and the implementation has to apply the options of DefaultParams itself.
+func (c *Client) With(opts ...Option) *Client {
+	child := *c
+
+	child.defaultOptions = make([]Option, 0, len(c.defaultOptions)+len(opts))
+	child.defaultOptions = append(child.defaultOptions, c.defaultOptions...)
+	child.defaultOptions = append(child.defaultOptions, opts...)
+
+	if service, ok := c.RegistrantAlert.(*registrantAlertServiceOp); ok {
+		child.RegistrantAlert = &registrantAlertServiceOp{client: &child, baseURL: service.baseURL}
+	}
+
+	return &child
+}
+
+// clientCalendar returns the calendar of the client or DefaultCalendar if it's not set.
+func (c *Client) clientCalendar() Calendar {
+	if c.calendar != nil {
+		return *c.calendar
+	}
+	return DefaultCalendar
+}
+
+// today returns the current date of the client's calendar.
+func (c *Client) today() time.Time {
+	return c.clientCalendar().Today()
+}
+
+// DefaultParams returns the parameters set by the default options of the client.
+func (c *Client) DefaultParams() (RequestParams, error) {
+	return ParamsFromOptions(c.defaultOptions...)
+}
+
 // Client is the client for Registrant Alert API services.
 type Client struct {
 	client *http.Client
@@ -69,6 +120,9 @@ type Client struct {
 	userAgent string
 	apiKey    string
 
+	defaultOptions []Option
+	calendar       *Calendar
+
 	// RegistrantAlert is an interface for Registrant Alert API
 	RegistrantAlert
 }
@@ -2,11 +2,13 @@ package registrantalert
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strconv"
 	"testing"
+	"time"
 )
 
 const (
@@ -1161,3 +1163,257 @@ func TestRegistrantAlertAdvancedRawData(t *testing.T) {
 		})
 	}
 }
+
+// fixedCalendar returns the calendar with the clock stopped at the time.
+func fixedCalendar(now time.Time) *Calendar {
+	return &Calendar{Now: func() time.Time { return now }}
+}
+
+// TestClientDefaultOptions tests the precedence of the client-wide default options.
+func TestClientDefaultOptions(t *testing.T) {
+	t.Parallel()
+
+	var got wireRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		got = wireRequest{}
+		if err := json.NewDecoder(req.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+		_, _ = w.Write([]byte(`{"domainsCount":1}`))
+	}))
+	defer server.Close()
+
+	apiURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parent := NewClient(apiKey, ClientParams{
+		HTTPClient:             server.Client(),
+		RegistrantAlertBaseURL: apiURL,
+		Calendar:               fixedCalendar(time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)),
+		DefaultOptions: []Option{
+			OptionResponseFormat("xml"),
+			OptionSinceDate(time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)),
+			OptionCreatedDateFrom(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)),
+		},
+	})
+	child := parent.With(OptionCreatedDateFrom(time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)))
+
+	terms := &BasicSearchTerms{Include: []string{"whois"}}
+
+	tests := []struct {
+		name    string
+		client  *Client
+		opts    []Option
+		raw     bool
+		want    RequestParams
+		wantErr string
+	}{
+		{
+			name:   "parent defaults",
+			client: parent,
+			raw:    true,
+			want:   RequestParams{ResponseFormat: "xml", SinceDate: "2022-10-01", CreatedDateFrom: "2022-01-01"},
+		},
+		{
+			name:   "child overrides parent",
+			client: child,
+			raw:    true,
+			want:   RequestParams{ResponseFormat: "xml", SinceDate: "2022-10-01", CreatedDateFrom: "2022-06-01"},
+		},
+		{
+			name:   "call overrides child",
+			client: child,
+			opts:   []Option{OptionSinceDate(time.Date(2022, 10, 15, 0, 0, 0, 0, time.UTC))},
+			raw:    true,
+			want:   RequestParams{ResponseFormat: "xml", SinceDate: "2022-10-15", CreatedDateFrom: "2022-06-01"},
+		},
+		{
+			name:   "preview enforces json",
+			client: child,
+			want:   RequestParams{ResponseFormat: "json", SinceDate: "2022-10-01", CreatedDateFrom: "2022-06-01"},
+		},
+		{
+			name:    "nil default option",
+			client:  child.With(nil),
+			raw:     true,
+			wantErr: `invalid argument: "DefaultOptions" can not contain nil`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = wireRequest{}
+
+			if tt.raw {
+				_, err = tt.client.BasicRawData(context.Background(), terms, tt.opts...)
+			} else {
+				_, _, err = tt.client.BasicPreview(context.Background(), terms, tt.opts...)
+			}
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr != "" {
+				return
+			}
+
+			if got.ResponseFormat != tt.want.ResponseFormat || got.SinceDate != tt.want.SinceDate ||
+				got.CreatedDateFrom != tt.want.CreatedDateFrom {
+				t.Errorf("request = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestOptionPunycodeFalse tests that the disabled Punycode is sent, since the API default is true.
+func TestOptionPunycodeFalse(t *testing.T) {
+	t.Parallel()
+
+	var got map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		got = nil
+		if err := json.NewDecoder(req.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+		_, _ = w.Write([]byte(`{"domainsCount":1}`))
+	}))
+	defer server.Close()
+
+	apiURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient(apiKey, ClientParams{HTTPClient: server.Client(), RegistrantAlertBaseURL: apiURL})
+
+	terms := &BasicSearchTerms{Include: []string{"whois"}}
+	for _, punycode := range []bool{false, true} {
+		if _, _, err := client.BasicPreview(context.Background(), terms, OptionPunycode(punycode)); err != nil {
+			t.Fatal(err)
+		}
+		if got["punycode"] != punycode {
+			t.Errorf("punycode = %v, want %v", got["punycode"], punycode)
+		}
+	}
+}
+
+// TestValidateRequest tests the validation of the request options.
+func TestValidateRequest(t *testing.T) {
+	t.Parallel()
+
+	today := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr string
+	}{
+		{
+			name: "valid options",
+			opts: []Option{
+				OptionResponseFormat("XML"),
+				OptionSinceDate(date(2022, 10, 1)),
+				OptionCreatedDateFrom(date(2022, 1, 1)),
+				OptionCreatedDateTo(date(2022, 1, 1)),
+				OptionExpiredDateFrom(date(2023, 1, 1)),
+			},
+		},
+		{
+			name:    "unknown response format",
+			opts:    []Option{OptionResponseFormat("csv")},
+			wantErr: `invalid argument: "responseFormat" must be json or xml.`,
+		},
+		{
+			name:    "zero date",
+			opts:    []Option{OptionUpdatedDateTo(time.Time{})},
+			wantErr: `invalid argument: "updatedDateTo" must not be the zero date.`,
+		},
+		{
+			name:    "inverted range",
+			opts:    []Option{OptionCreatedDateFrom(date(2022, 2, 1)), OptionCreatedDateTo(date(2022, 1, 1))},
+			wantErr: `invalid argument: "createdDateFrom" must not be after createdDateTo.`,
+		},
+		{
+			name:    "future since date",
+			opts:    []Option{OptionSinceDate(date(2022, 11, 5))},
+			wantErr: `invalid argument: "sinceDate" must not be in the future.`,
+		},
+		{
+			name:    "since date beyond look-back",
+			opts:    []Option{OptionSinceDate(date(2021, 10, 1))},
+			wantErr: `invalid argument: "sinceDate" must be within the last 12 months.`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &registrantAlertRequest{ResponseFormat: "json"}
+			for _, opt := range tt.opts {
+				opt(request)
+			}
+
+			checkErr(t, validateRequest(request, today), tt.wantErr)
+		})
+	}
+}
+
+// TestResponseFormat tests that RawData methods report the format of the returned body.
+func TestResponseFormat(t *testing.T) {
+	var accept string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		accept = req.Header.Get("Accept")
+		w.Header().Set("Content-Type", req.URL.Query().Get("type"))
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		contentType string
+		format      ResponseFormat
+		want        ResponseFormat
+	}{
+		{"application/json; charset=utf-8", JSON, JSON},
+		{"text/xml", XML, XML},
+		{"application/xml;charset=UTF-8", XML, XML},
+		{"text/html", JSON, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			api := newAPI(server, "/")
+			api.RegistrantAlert.(*registrantAlertServiceOp).baseURL.RawQuery = url.Values{"type": {tt.contentType}}.Encode()
+
+			resp, err := api.BasicRawData(context.Background(), &BasicSearchTerms{Include: []string{"whois"}},
+				OptionResponseFormat(tt.format))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := resp.Format(); got != tt.want {
+				t.Errorf("Format() = %v, want %v", got, tt.want)
+			}
+			if accept != tt.format.mediaType() {
+				t.Errorf("Accept = %v, want %v", accept, tt.format.mediaType())
+			}
+		})
+	}
+}
+
+// TestClientDefaultParams tests the introspection of the client-wide default options.
+func TestClientDefaultParams(t *testing.T) {
+	client := NewClient(apiKey, ClientParams{DefaultOptions: []Option{OptionPunycode(false)}}).
+		With(OptionSinceDate(time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)))
+
+	params, err := client.DefaultParams()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "punycode=false&sinceDate=2022-10-01"; params.String() != want {
+		t.Errorf("DefaultParams() = %v, want %v", params, want)
+	}
+
+	_, err = client.With(nil).DefaultParams()
+	checkErr(t, err, `invalid argument: "Option" can not be nil`)
+}
@@ -0,0 +1,345 @@
+package registrantalert
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// List of environment variables read by ConfigFromEnv.
+const (
+	EnvConfigFile   = "REGISTRANT_ALERT_CONFIG"
+	EnvAPIKey       = "REGISTRANT_ALERT_API_KEY"
+	EnvBaseURL      = "REGISTRANT_ALERT_BASE_URL"
+	EnvTimeout      = "REGISTRANT_ALERT_TIMEOUT"
+	EnvProxy        = "REGISTRANT_ALERT_PROXY"
+	EnvMaxRetries   = "REGISTRANT_ALERT_MAX_RETRIES"
+	EnvRetryBackoff = "REGISTRANT_ALERT_RETRY_BACKOFF"
+	EnvRateLimit    = "REGISTRANT_ALERT_RATE_LIMIT"
+)
+
+// Duration is a helper wrapper on time.Duration encoded as a string like "30s" or "1m30s".
+type Duration time.Duration
+
+// UnmarshalJSON decodes the duration from a string.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	str, err := unmarshalString(b)
+	if err != nil {
+		return err
+	}
+	if str == "" {
+		*d = 0
+		return nil
+	}
+	v, err := time.ParseDuration(str)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalJSON encodes the duration as a string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// RetryConfig is the retry policy of the client.
+type RetryConfig struct {
+	// MaxRetries is the number of times a failed request is resent. Zero disables retries.
+	// Purchase requests are resent only on 429 and connection errors, so credits are not charged twice.
+	MaxRetries int `json:"maxRetries,omitempty"`
+
+	// Backoff is the delay before the first retry. It doubles on every next attempt.
+	Backoff Duration `json:"backoff,omitempty"`
+}
+
+// Config is the client configuration which can be loaded from a JSON file or environment variables.
+type Config struct {
+	// APIKey is the user's API key.
+	APIKey string `json:"apiKey"`
+
+	// BaseURL is the endpoint for 'Registrant Alert API' service.
+	BaseURL string `json:"baseURL,omitempty"`
+
+	// Timeout is the time limit for a request, including retries.
+	Timeout Duration `json:"timeout,omitempty"`
+
+	// Proxy is the URL of the proxy server.
+	Proxy string `json:"proxy,omitempty"`
+
+	// Retry is the retry policy.
+	Retry RetryConfig `json:"retry,omitempty"`
+
+	// RateLimit is the maximum number of requests per second. Zero means no limit.
+	RateLimit float64 `json:"rateLimit,omitempty"`
+
+	// Options are the default options of the requests.
+	Options RequestParams `json:"options,omitempty"`
+}
+
+// LoadConfig reads the JSON configuration file.
+func LoadConfig(path string) (*Config, error) {
+	var cfg Config
+
+	if err := decodeConfigFile(path, &cfg); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// ParseConfig decodes and validates the JSON configuration.
+func ParseConfig(r io.Reader) (*Config, error) {
+	var cfg Config
+
+	if err := decodeConfig(r, &cfg); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// decodeConfig decodes the JSON configuration rejecting unknown fields.
+func decodeConfig(r io.Reader, cfg *Config) error {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(cfg); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			return &ArgError{"config." + typeErr.Field, "must be " + typeErr.Type.String() + "."}
+		}
+
+		return fmt.Errorf("cannot parse config: %w", err)
+	}
+
+	return nil
+}
+
+// decodeConfigFile decodes the JSON configuration file.
+func decodeConfigFile(path string, cfg *Config) (err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("cannot open config: %w", err)
+	}
+
+	defer func() {
+		if cerr := f.Close(); err == nil && cerr != nil {
+			err = fmt.Errorf("cannot close config: %w", cerr)
+		}
+	}()
+
+	return decodeConfig(f, cfg)
+}
+
+// ConfigFromEnv loads the configuration from the environment variables.
+// If REGISTRANT_ALERT_CONFIG is set, the file it points to is loaded first,
+// and the other variables override its values.
+func ConfigFromEnv() (*Config, error) {
+	var cfg Config
+
+	if path := os.Getenv(EnvConfigFile); path != "" {
+		if err := decodeConfigFile(path, &cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if v, ok := os.LookupEnv(EnvAPIKey); ok {
+		cfg.APIKey = v
+	}
+
+	if v, ok := os.LookupEnv(EnvBaseURL); ok {
+		cfg.BaseURL = v
+	}
+
+	if v, ok := os.LookupEnv(EnvProxy); ok {
+		cfg.Proxy = v
+	}
+
+	if v, ok := os.LookupEnv(EnvTimeout); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, &ArgError{EnvTimeout, "must be a duration, e.g. 30s."}
+		}
+		cfg.Timeout = Duration(d)
+	}
+
+	if v, ok := os.LookupEnv(EnvMaxRetries); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, &ArgError{EnvMaxRetries, "must be an integer."}
+		}
+		cfg.Retry.MaxRetries = n
+	}
+
+	if v, ok := os.LookupEnv(EnvRetryBackoff); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, &ArgError{EnvRetryBackoff, "must be a duration, e.g. 500ms."}
+		}
+		cfg.Retry.Backoff = Duration(d)
+	}
+
+	if v, ok := os.LookupEnv(EnvRateLimit); ok {
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+			return nil, &ArgError{EnvRateLimit, "must be a number."}
+		}
+		cfg.RateLimit = n
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// Validate checks the configuration values.
+func (c *Config) Validate() error {
+	if c.APIKey == "" {
+		return &ArgError{"config.apiKey", "is required."}
+	}
+
+	if c.BaseURL != "" {
+		if _, ok := parseHTTPURL(c.BaseURL); !ok {
+			return &ArgError{"config.baseURL", "must be an absolute http(s) URL."}
+		}
+	}
+
+	if c.Timeout < 0 {
+		return &ArgError{"config.timeout", "must not be negative."}
+	}
+
+	if c.Proxy != "" {
+		if _, ok := parseProxyURL(c.Proxy); !ok {
+			return &ArgError{"config.proxy", "must be an absolute http(s) or socks5 URL."}
+		}
+	}
+
+	if c.Retry.MaxRetries < 0 {
+		return &ArgError{"config.retry.maxRetries", "must not be negative."}
+	}
+
+	if c.Retry.Backoff < 0 {
+		return &ArgError{"config.retry.backoff", "must not be negative."}
+	}
+
+	if c.RateLimit < 0 {
+		return &ArgError{"config.rateLimit", "must not be negative."}
+	}
+
+	if math.IsNaN(c.RateLimit) || math.IsInf(c.RateLimit, 0) || c.RateLimit > maxRateLimit {
+		return &ArgError{"config.rateLimit", "must be a number up to 1e9."}
+	}
+
+	if _, err := c.Options.Options(); err != nil {
+		var argErr *ArgError
+		if errors.As(err, &argErr) {
+			return &ArgError{"config.options." + argErr.Name, argErr.Message}
+		}
+		return err
+	}
+
+	return nil
+}
+
+// parseHTTPURL parses the absolute http(s) URL.
+func parseHTTPURL(rawURL string) (*url.URL, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, false
+	}
+
+	return u, true
+}
+
+// parseProxyURL parses the absolute http(s) or socks5 URL of the proxy server.
+func parseProxyURL(rawURL string) (*url.URL, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") || u.Host == "" {
+		return nil, false
+	}
+
+	return u, true
+}
+
+// ClientParams converts the configuration to ClientParams.
+func (c *Config) ClientParams() (ClientParams, error) {
+	var params ClientParams
+
+	if err := c.Validate(); err != nil {
+		return params, err
+	}
+
+	if c.BaseURL != "" {
+		params.RegistrantAlertBaseURL, _ = parseHTTPURL(c.BaseURL)
+	}
+
+	params.DefaultOptions, _ = c.Options.Options()
+
+	if c.Timeout == 0 && c.Proxy == "" && c.Retry.MaxRetries == 0 && c.RateLimit == 0 {
+		return params, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if c.Proxy != "" {
+		proxyURL, _ := parseProxyURL(c.Proxy)
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	var roundTripper http.RoundTripper = transport
+	if c.RateLimit > 0 {
+		roundTripper = newRateLimitTransport(roundTripper, c.RateLimit)
+	}
+	if c.Retry.MaxRetries > 0 {
+		roundTripper = &retryTransport{
+			next:       roundTripper,
+			maxRetries: c.Retry.MaxRetries,
+			backoff:    time.Duration(c.Retry.Backoff),
+		}
+	}
+
+	params.HTTPClient = &http.Client{
+		Transport: roundTripper,
+		Timeout:   time.Duration(c.Timeout),
+	}
+
+	return params, nil
+}
+
+// NewClientFromConfig creates Client with the specified configuration.
+func NewClientFromConfig(cfg *Config) (*Client, error) {
+	params, err := cfg.ClientParams()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClient(cfg.APIKey, params), nil
+}
+
+// NewClientFromEnv creates Client configured with the environment variables.
+func NewClientFromEnv() (*Client, error) {
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClientFromConfig(cfg)
+}
@@ -0,0 +1,234 @@
+package registrantalert
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestParseConfig tests the ParseConfig function.
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name: "full config",
+			config: `{"apiKey":"at_key","baseURL":"https://example.com/api/v2","timeout":"30s",
+"proxy":"http://127.0.0.1:3128","retry":{"maxRetries":3,"backoff":"1s"},"rateLimit":2,
+"options":{"punycode":false,"sinceDate":"2022-10-01"}}`,
+			wantErr: "",
+		},
+		{
+			name:    "missing api key",
+			config:  `{"timeout":"30s"}`,
+			wantErr: `invalid argument: "config.apiKey" is required.`,
+		},
+		{
+			name:    "relative base url",
+			config:  `{"apiKey":"at_key","baseURL":"/api/v2"}`,
+			wantErr: `invalid argument: "config.baseURL" must be an absolute http(s) URL.`,
+		},
+		{
+			name:    "proxy without scheme",
+			config:  `{"apiKey":"at_key","proxy":"localhost:3128"}`,
+			wantErr: `invalid argument: "config.proxy" must be an absolute http(s) or socks5 URL.`,
+		},
+		{
+			name:    "negative retries",
+			config:  `{"apiKey":"at_key","retry":{"maxRetries":-1}}`,
+			wantErr: `invalid argument: "config.retry.maxRetries" must not be negative.`,
+		},
+		{
+			name:    "too high rate limit",
+			config:  `{"apiKey":"at_key","rateLimit":1e10}`,
+			wantErr: `invalid argument: "config.rateLimit" must be a number up to 1e9.`,
+		},
+		{
+			name:    "wrong type",
+			config:  `{"apiKey":"at_key","rateLimit":"fast"}`,
+			wantErr: `invalid argument: "config.rateLimit" must be float64.`,
+		},
+		{
+			name:    "invalid date",
+			config:  `{"apiKey":"at_key","options":{"createdDateTo":"01/02/2022"}}`,
+			wantErr: `invalid argument: "config.options.createdDateTo" must be a date in YYYY-MM-DD format.`,
+		},
+		{
+			name:    "unknown field",
+			config:  `{"apiKey":"at_key","retries":3}`,
+			wantErr: `cannot parse config: json: unknown field "retries"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ParseConfig(strings.NewReader(tt.config))
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr != "" {
+				return
+			}
+
+			params, err := cfg.ClientParams()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if params.HTTPClient == nil || params.HTTPClient.Timeout != 30*time.Second {
+				t.Errorf("ClientParams() HTTPClient = %v, want timeout 30s", params.HTTPClient)
+			}
+			if params.RegistrantAlertBaseURL.String() != "https://example.com/api/v2" {
+				t.Errorf("ClientParams() base URL = %v", params.RegistrantAlertBaseURL)
+			}
+			if len(params.DefaultOptions) != 2 {
+				t.Errorf("ClientParams() got %d default options, want 2", len(params.DefaultOptions))
+			}
+		})
+	}
+}
+
+// TestConfigFromEnv tests the ConfigFromEnv function.
+func TestConfigFromEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(path, []byte(`{"apiKey":"at_file","timeout":"10s"}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(EnvConfigFile, path)
+	t.Setenv(EnvAPIKey, "at_env")
+	t.Setenv(EnvMaxRetries, "2")
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.APIKey != "at_env" || cfg.Timeout != Duration(10*time.Second) || cfg.Retry.MaxRetries != 2 {
+		t.Errorf("ConfigFromEnv() = %+v", cfg)
+	}
+
+	t.Setenv(EnvRateLimit, "many")
+
+	_, err = ConfigFromEnv()
+	checkErr(t, err, `invalid argument: "`+EnvRateLimit+`" must be a number.`)
+
+	t.Setenv(EnvRateLimit, "NaN")
+
+	_, err = ConfigFromEnv()
+	checkErr(t, err, `invalid argument: "`+EnvRateLimit+`" must be a number.`)
+
+	t.Setenv(EnvRateLimit, "0")
+	t.Setenv(EnvConfigFile, filepath.Join(t.TempDir(), "missing.json"))
+
+	_, err = ConfigFromEnv()
+	if err == nil || !strings.HasPrefix(err.Error(), "cannot open config: ") {
+		t.Errorf("ConfigFromEnv() error = %v, want cannot open config", err)
+	}
+}
+
+// TestRetryTransport tests that failed requests are resent with the same body.
+func TestRetryTransport(t *testing.T) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		b := make([]byte, 4)
+		n, _ := req.Body.Read(b)
+		if string(b[:n]) != "body" {
+			t.Errorf("got body %q", b[:n])
+		}
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &retryTransport{
+		next:       http.DefaultTransport,
+		maxRetries: 2,
+		backoff:    time.Millisecond,
+	}}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, strings.NewReader("body"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || atomic.LoadInt32(&calls) != 3 {
+		t.Errorf("got status %d after %d calls, want 200 after 3 calls", resp.StatusCode, calls)
+	}
+}
+
+// TestRetryTransportPurchase tests that purchase requests are resent only on 429 and dial errors.
+func TestRetryTransportPurchase(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		status    int
+		wantCalls int32
+	}{
+		{"preview on 503", `{"mode":"preview"}`, http.StatusServiceUnavailable, 3},
+		{"purchase on 503", `{"mode":"purchase"}`, http.StatusServiceUnavailable, 1},
+		{"purchase on 429", `{"mode":"purchase"}`, http.StatusTooManyRequests, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			client := &http.Client{Transport: &retryTransport{
+				next:       http.DefaultTransport,
+				maxRetries: 2,
+				backoff:    time.Millisecond,
+			}}
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("got %d calls, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+// TestNotSent tests the detection of the errors that happened before sending the request.
+func TestNotSent(t *testing.T) {
+	dialErr := &url.Error{Op: "Post", URL: "http://example.com", Err: &net.OpError{Op: "dial", Err: errors.New("refused")}}
+	readErr := &url.Error{Op: "Post", URL: "http://example.com", Err: &net.OpError{Op: "read", Err: errors.New("reset")}}
+
+	if !notSent(dialErr) || !notSent(&net.DNSError{Err: "no such host"}) {
+		t.Error("notSent() = false for dial errors")
+	}
+	if notSent(readErr) || notSent(io.ErrUnexpectedEOF) {
+		t.Error("notSent() = true for errors after sending")
+	}
+}
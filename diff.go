@@ -0,0 +1,153 @@
+package registrantalert
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SnapshotDiff is the difference between two responses of the same search.
+// The domains are matched by the case-insensitive name and sorted by it.
+type SnapshotDiff struct {
+	// Appeared are the domains present only in the new response.
+	Appeared []DomainItem `json:"appeared"`
+
+	// Disappeared are the domains present only in the old response.
+	Disappeared []DomainItem `json:"disappeared"`
+
+	// Changed are the domains with a different action or date.
+	Changed []DomainChange `json:"changed"`
+}
+
+// DomainChange is the domain present in both responses with a different action or date.
+type DomainChange struct {
+	DomainName string     `json:"domainName"`
+	Old        DomainItem `json:"old"`
+	New        DomainItem `json:"new"`
+}
+
+// snapshotIndex returns the domains of the response by the lowercase name.
+// If the domain occurs several times then the latest event is kept.
+func snapshotIndex(resp *RegistrantAlertResponse) map[string]DomainItem {
+	index := make(map[string]DomainItem)
+	if resp == nil {
+		return index
+	}
+
+	for _, item := range resp.DomainsList {
+		key := strings.ToLower(item.DomainName)
+		if prev, ok := index[key]; ok && time.Time(prev.Date).After(time.Time(item.Date)) {
+			continue
+		}
+		index[key] = item
+	}
+
+	return index
+}
+
+// Diff compares two responses of the same search. A nil response is treated as empty.
+func Diff(old, new *RegistrantAlertResponse) *SnapshotDiff {
+	oldIndex, newIndex := snapshotIndex(old), snapshotIndex(new)
+
+	d := &SnapshotDiff{
+		Appeared:    []DomainItem{},
+		Disappeared: []DomainItem{},
+		Changed:     []DomainChange{},
+	}
+
+	for key, newItem := range newIndex {
+		oldItem, ok := oldIndex[key]
+		switch {
+		case !ok:
+			d.Appeared = append(d.Appeared, newItem)
+		case oldItem.Action != newItem.Action || !oldItem.Date.Equal(newItem.Date):
+			d.Changed = append(d.Changed, DomainChange{DomainName: newItem.DomainName, Old: oldItem, New: newItem})
+		}
+	}
+
+	for key, oldItem := range oldIndex {
+		if _, ok := newIndex[key]; !ok {
+			d.Disappeared = append(d.Disappeared, oldItem)
+		}
+	}
+
+	sortItems := func(items []DomainItem) {
+		sort.Slice(items, func(i, j int) bool {
+			return strings.ToLower(items[i].DomainName) < strings.ToLower(items[j].DomainName)
+		})
+	}
+	sortItems(d.Appeared)
+	sortItems(d.Disappeared)
+	sort.Slice(d.Changed, func(i, j int) bool {
+		return strings.ToLower(d.Changed[i].DomainName) < strings.ToLower(d.Changed[j].DomainName)
+	})
+
+	return d
+}
+
+// IsEmpty reports whether the responses have the same domains.
+func (d *SnapshotDiff) IsEmpty() bool {
+	return len(d.Appeared) == 0 && len(d.Disappeared) == 0 && len(d.Changed) == 0
+}
+
+// formatDate returns the date in the YYYY-MM-DD format or "-" if it's empty.
+func formatDate(t Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return time.Time(t).Format(dateFormat)
+}
+
+// WriteReport writes the human-readable report of the difference.
+func (d *SnapshotDiff) WriteReport(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	if d.IsEmpty() {
+		bw.WriteString("No changes.\n")
+	}
+
+	sections := []struct {
+		title  string
+		prefix string
+		items  []DomainItem
+	}{
+		{"Appeared", "+", d.Appeared},
+		{"Disappeared", "-", d.Disappeared},
+	}
+
+	for _, section := range sections {
+		if len(section.items) == 0 {
+			continue
+		}
+
+		bw.WriteString(section.title + " (" + strconv.Itoa(len(section.items)) + "):\n")
+		for _, item := range section.items {
+			bw.WriteString("  " + section.prefix + " " + item.DomainName + " " + string(item.Action) +
+				" " + formatDate(item.Date) + "\n")
+		}
+	}
+
+	if len(d.Changed) > 0 {
+		bw.WriteString("Changed (" + strconv.Itoa(len(d.Changed)) + "):\n")
+		for _, change := range d.Changed {
+			var parts []string
+			if change.Old.Action != change.New.Action {
+				parts = append(parts, "action "+string(change.Old.Action)+" -> "+string(change.New.Action))
+			}
+			if !change.Old.Date.Equal(change.New.Date) {
+				parts = append(parts, "date "+formatDate(change.Old.Date)+" -> "+formatDate(change.New.Date))
+			}
+			bw.WriteString("  ~ " + change.DomainName + ": " + strings.Join(parts, ", ") + "\n")
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("cannot write report: %w", err)
+	}
+
+	return nil
+}
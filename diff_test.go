@@ -0,0 +1,89 @@
+package registrantalert
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestDiff tests the comparison of two responses and the report.
+func TestDiff(t *testing.T) {
+	day := func(d int) Time {
+		return Time(time.Date(2022, 10, d, 0, 0, 0, 0, time.UTC))
+	}
+
+	old := &RegistrantAlertResponse{DomainsList: []DomainItem{
+		{"batchwhois.com", Added, day(20)},
+		{"whoislookup.info", Added, day(21)},
+		{"whoisdodster.com", Added, day(22)},
+		{"gone.com", Updated, day(23)},
+	}}
+	new := &RegistrantAlertResponse{DomainsList: []DomainItem{
+		{"BatchWhois.com", Added, day(20)},
+		{"whoislookup.info", Updated, day(21)},
+		{"whoisdodster.com", Added, emptyTime},
+		{"whoisdodster.com", Dropped, day(28)},
+		{"new.com", Discovered, day(27)},
+	}}
+
+	d := Diff(old, new)
+
+	want := &SnapshotDiff{
+		Appeared:    []DomainItem{{"new.com", Discovered, day(27)}},
+		Disappeared: []DomainItem{{"gone.com", Updated, day(23)}},
+		Changed: []DomainChange{
+			{"whoisdodster.com", DomainItem{"whoisdodster.com", Added, day(22)}, DomainItem{"whoisdodster.com", Dropped, day(28)}},
+			{"whoislookup.info", DomainItem{"whoislookup.info", Added, day(21)}, DomainItem{"whoislookup.info", Updated, day(21)}},
+		},
+	}
+	if !reflect.DeepEqual(d, want) {
+		t.Errorf("Diff() = %+v, want %+v", d, want)
+	}
+
+	var b bytes.Buffer
+	if err := d.WriteReport(&b); err != nil {
+		t.Fatal(err)
+	}
+	wantReport := `Appeared (1):
+  + new.com discovered 2022-10-27
+Disappeared (1):
+  - gone.com updated 2022-10-23
+Changed (2):
+  ~ whoisdodster.com: action added -> dropped, date 2022-10-22 -> 2022-10-28
+  ~ whoislookup.info: action added -> updated
+`
+	if b.String() != wantReport {
+		t.Errorf("WriteReport() = %s, want %s", b.String(), wantReport)
+	}
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded SnapshotDiff
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&decoded, d) {
+		t.Errorf("json round trip = %+v, want %+v", decoded, d)
+	}
+
+	empty := Diff(nil, &RegistrantAlertResponse{})
+	if !empty.IsEmpty() {
+		t.Errorf("Diff(nil, empty) = %+v", empty)
+	}
+	b.Reset()
+	_ = empty.WriteReport(&b)
+	if b.String() != "No changes.\n" {
+		t.Errorf("WriteReport() = %s", b.String())
+	}
+
+	var offset, utc RegistrantAlertResponse
+	_ = json.Unmarshal([]byte(`{"domainsList":[{"domainName":"a.com","action":"added","date":"2022-10-30T02:00:00+02:00"}]}`), &offset)
+	_ = json.Unmarshal([]byte(`{"domainsList":[{"domainName":"a.com","action":"added","date":"2022-10-30T00:00:00Z"}]}`), &utc)
+	if same := Diff(&offset, &utc); !same.IsEmpty() {
+		t.Errorf("Diff() of the same instant = %+v", same)
+	}
+}
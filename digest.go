@@ -0,0 +1,382 @@
+package registrantalert
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// defaultDigestTitle is the title of the digest when none is specified.
+const defaultDigestTitle = "Registrant Alert digest"
+
+// actionOrder is the order of the action groups in the digest.
+var actionOrder = map[Action]int{
+	Added:      0,
+	Discovered: 1,
+	Updated:    2,
+	Dropped:    3,
+}
+
+// DigestOptions configures the digest.
+type DigestOptions struct {
+	// Title is the digest title and the email subject. Default: "Registrant Alert digest".
+	Title string
+
+	// DomainURL returns the link of the domain.
+	// If it's nil then the WHOIS lookup page on whoisxmlapi.com is used.
+	DomainURL func(domainName string) string
+
+	// MaxDomains is the maximum number of domains listed per action group, the rest is only counted.
+	// Zero means no limit.
+	MaxDomains int
+
+	// GeneratedAt is the digest time. If it's zero then the current time is used.
+	GeneratedAt time.Time
+}
+
+// Digest is the summary of the search results grouped by search and action.
+type Digest struct {
+	Title       string
+	GeneratedAt time.Time
+	Total       int
+	Sections    []DigestSection
+}
+
+// DigestSection is the part of the digest describing a single search.
+type DigestSection struct {
+	Search string
+	Terms  string
+	RunAt  time.Time
+	Total  int
+	Groups []DigestGroup
+}
+
+// DigestGroup is the list of domains with the same action.
+type DigestGroup struct {
+	Action  Action
+	Count   int
+	Domains []DigestDomain
+	Omitted int
+}
+
+// DigestDomain is the domain listed in the digest.
+type DigestDomain struct {
+	Name string
+	Date string
+	URL  string
+}
+
+// defaultDomainURL returns the WHOIS lookup page of the domain.
+func defaultDomainURL(domainName string) string {
+	return "https://whois.whoisxmlapi.com/lookup?q=" + url.QueryEscape(domainName)
+}
+
+// NewDigest groups the results by search and action.
+func NewDigest(results []SearchResult, opts DigestOptions) *Digest {
+	domainURL := opts.DomainURL
+	if domainURL == nil {
+		domainURL = defaultDomainURL
+	}
+
+	d := &Digest{
+		Title:       opts.Title,
+		GeneratedAt: opts.GeneratedAt,
+	}
+	if d.Title == "" {
+		d.Title = defaultDigestTitle
+	}
+	if d.GeneratedAt.IsZero() {
+		d.GeneratedAt = time.Now()
+	}
+
+	for _, result := range results {
+		section := DigestSection{
+			Search: result.Search.String(),
+			Terms:  result.Search.Terms(),
+			RunAt:  result.RunAt,
+		}
+
+		groups := make(map[Action]*DigestGroup)
+		for _, item := range result.domains() {
+			group, ok := groups[item.Action]
+			if !ok {
+				group = &DigestGroup{Action: item.Action}
+				groups[item.Action] = group
+			}
+
+			group.Count++
+			section.Total++
+
+			if opts.MaxDomains > 0 && len(group.Domains) >= opts.MaxDomains {
+				group.Omitted++
+				continue
+			}
+
+			var date string
+			if !item.Date.IsZero() {
+				date = time.Time(item.Date).Format(dateFormat)
+			}
+			group.Domains = append(group.Domains, DigestDomain{
+				Name: item.DomainName,
+				Date: date,
+				URL:  domainURL(item.DomainName),
+			})
+		}
+
+		for _, group := range groups {
+			section.Groups = append(section.Groups, *group)
+		}
+		sort.Slice(section.Groups, func(i, j int) bool {
+			a, b := section.Groups[i].Action, section.Groups[j].Action
+			oa, okA := actionOrder[a]
+			ob, okB := actionOrder[b]
+			if okA != okB {
+				return okA
+			}
+			if oa != ob {
+				return oa < ob
+			}
+			return a < b
+		})
+
+		d.Total += section.Total
+		d.Sections = append(d.Sections, section)
+	}
+
+	return d
+}
+
+// digestFuncs are the helper functions of the digest templates.
+var digestFuncs = map[string]interface{}{
+	"date": func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format("2006-01-02 15:04 MST")
+	},
+}
+
+// DigestTextTemplate is the template of the plain text digest.
+// It can be replaced to customize the output, the template data is *Digest.
+var DigestTextTemplate = texttemplate.Must(texttemplate.New("digest.txt").Funcs(digestFuncs).Parse(
+	`{{.Title}}
+Generated: {{date .GeneratedAt}}
+Total domains: {{.Total}}
+{{range .Sections}}
+== {{.Search}} ({{.Total}}) ==
+{{- if and .Terms (ne .Terms .Search)}}
+Search terms: {{.Terms}}
+{{- end}}
+{{- if not .RunAt.IsZero}}
+Run at: {{date .RunAt}}
+{{- end}}
+{{- range .Groups}}
+
+{{.Action}}: {{.Count}}
+{{- range .Domains}}
+  - {{.Name}}{{if .Date}} ({{.Date}}){{end}}{{if .URL}} {{.URL}}{{end}}
+{{- end}}
+{{- if .Omitted}}
+  ... and {{.Omitted}} more
+{{- end}}
+{{- else}}
+No domains.
+{{- end}}
+{{end}}`))
+
+// DigestHTMLTemplate is the template of the HTML digest.
+// It can be replaced to customize the output, the template data is *Digest.
+var DigestHTMLTemplate = htmltemplate.Must(htmltemplate.New("digest.html").Funcs(digestFuncs).Parse(
+	`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Title}}</title></head>
+<body>
+<h1>{{.Title}}</h1>
+<p>Generated: {{date .GeneratedAt}}<br>Total domains: {{.Total}}</p>
+{{range .Sections}}
+<h2>{{.Search}} ({{.Total}})</h2>
+{{- if and .Terms (ne .Terms .Search)}}
+<p>Search terms: {{.Terms}}</p>
+{{- end}}
+{{- range .Groups}}
+<h3>{{.Action}}: {{.Count}}</h3>
+<ul>
+{{- range .Domains}}
+<li>{{if .URL}}<a href="{{.URL}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}{{if .Date}} ({{.Date}}){{end}}</li>
+{{- end}}
+{{- if .Omitted}}
+<li>... and {{.Omitted}} more</li>
+{{- end}}
+</ul>
+{{- else}}
+<p>No domains.</p>
+{{- end}}
+{{end}}
+</body>
+</html>
+`))
+
+// RenderText writes the plain text digest.
+func (d *Digest) RenderText(w io.Writer) error {
+	if err := DigestTextTemplate.Execute(w, d); err != nil {
+		return fmt.Errorf("cannot render digest: %w", err)
+	}
+	return nil
+}
+
+// RenderHTML writes the HTML digest.
+func (d *Digest) RenderHTML(w io.Writer) error {
+	if err := DigestHTMLTemplate.Execute(w, d); err != nil {
+		return fmt.Errorf("cannot render digest: %w", err)
+	}
+	return nil
+}
+
+// Subject returns the email subject of the digest.
+func (d *Digest) Subject() string {
+	return d.Title + " (" + strconv.Itoa(d.Total) + " domains)"
+}
+
+// parseAddress parses the email address, so it can't inject the message headers.
+func parseAddress(name, s string) (*mail.Address, error) {
+	address, err := mail.ParseAddress(s)
+	if err != nil || strings.ContainsAny(address.Address, "\r\n") {
+		return nil, &ArgError{name, "must be a valid email address."}
+	}
+	return address, nil
+}
+
+// formatAddress returns the address for the message header. The display name is Q-encoded if needed.
+func formatAddress(address *mail.Address) string {
+	if address.Name == "" {
+		return address.Address
+	}
+	return address.String()
+}
+
+// Message returns the MIME email message with the text and HTML parts of the digest.
+// The addresses are validated and the subject is Q-encoded, so they can't inject headers.
+func (d *Digest) Message(from string, to []string) ([]byte, error) {
+	sender, err := parseAddress("from", from)
+	if err != nil {
+		return nil, err
+	}
+
+	toList := make([]string, len(to))
+	for i, s := range to {
+		address, err := parseAddress("to."+strconv.Itoa(i), s)
+		if err != nil {
+			return nil, err
+		}
+		toList[i] = formatAddress(address)
+	}
+
+	var b bytes.Buffer
+
+	mw := multipart.NewWriter(&b)
+
+	b.WriteString("From: " + formatAddress(sender) + "\r\n")
+	b.WriteString("To: " + strings.Join(toList, ", ") + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", d.Subject()) + "\r\n")
+	b.WriteString("Date: " + d.GeneratedAt.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: multipart/alternative; boundary=" + mw.Boundary() + "\r\n\r\n")
+
+	parts := []struct {
+		contentType string
+		render      func(io.Writer) error
+	}{
+		{"text/plain; charset=utf-8", d.RenderText},
+		{"text/html; charset=utf-8", d.RenderHTML},
+	}
+
+	for _, part := range parts {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		qw := quotedprintable.NewWriter(pw)
+		if err := part.render(qw); err != nil {
+			return nil, err
+		}
+		if err := qw.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// SMTPSender sends the digests by email.
+type SMTPSender struct {
+	// Addr is the SMTP server address, e.g. "localhost:25".
+	Addr string
+
+	// Auth is the SMTP authentication. If it's nil then no authentication is used.
+	Auth smtp.Auth
+
+	// From is the sender address.
+	From string
+
+	// To are the recipient addresses.
+	To []string
+}
+
+// Send sends the digest as the email with the text and HTML parts.
+func (s *SMTPSender) Send(d *Digest) error {
+	if s.Addr == "" {
+		return &ArgError{"SMTPSender.Addr", "is required."}
+	}
+	if s.From == "" {
+		return &ArgError{"SMTPSender.From", "is required."}
+	}
+	if len(s.To) == 0 {
+		return &ArgError{"SMTPSender.To", "must have at least 1 item."}
+	}
+
+	from, err := parseAddress("SMTPSender.From", s.From)
+	if err != nil {
+		return err
+	}
+
+	to := make([]string, len(s.To))
+	for i, recipient := range s.To {
+		address, err := parseAddress("SMTPSender.To."+strconv.Itoa(i), recipient)
+		if err != nil {
+			return err
+		}
+		to[i] = address.Address
+	}
+
+	msg, err := d.Message(s.From, s.To)
+	if err != nil {
+		return fmt.Errorf("cannot build message: %w", err)
+	}
+
+	if err := smtp.SendMail(s.Addr, s.Auth, from.Address, to, msg); err != nil {
+		return fmt.Errorf("cannot send digest: %w", err)
+	}
+
+	return nil
+}
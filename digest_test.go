@@ -0,0 +1,205 @@
+package registrantalert
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testSearchResults returns the sample of search results for testing.
+func testSearchResults() []SearchResult {
+	date := Time(time.Date(2022, 10, 30, 0, 0, 0, 0, time.UTC))
+
+	return []SearchResult{
+		{
+			Search: Search{Name: "Whois brand", BasicSearchTerms: &BasicSearchTerms{Include: []string{"whois"}}},
+			RunAt:  time.Date(2022, 11, 1, 6, 0, 0, 0, time.UTC),
+			Response: &RegistrantAlertResponse{DomainsList: []DomainItem{
+				{"dropped.com", Dropped, date},
+				{"first.com", Added, date},
+				{"second.com", Added, date},
+				{"third.com", Added, date},
+			}},
+		},
+		{
+			Search:   Search{BasicSearchTerms: &BasicSearchTerms{Include: []string{"<lookup>"}}},
+			Response: &RegistrantAlertResponse{},
+		},
+	}
+}
+
+// TestDigest tests the digest rendering.
+func TestDigest(t *testing.T) {
+	d := NewDigest(testSearchResults(), DigestOptions{
+		MaxDomains:  2,
+		GeneratedAt: time.Date(2022, 11, 1, 7, 0, 0, 0, time.UTC),
+	})
+
+	if d.Total != 4 || len(d.Sections) != 2 || d.Sections[0].Groups[0].Action != Added ||
+		d.Sections[0].Groups[0].Omitted != 1 || d.Sections[0].Groups[1].Action != Dropped {
+		t.Fatalf("NewDigest() = %+v", d)
+	}
+
+	var text bytes.Buffer
+	if err := d.RenderText(&text); err != nil {
+		t.Fatal(err)
+	}
+
+	const wantText = `Registrant Alert digest
+Generated: 2022-11-01 07:00 UTC
+Total domains: 4
+
+== Whois brand (4) ==
+Search terms: include: "whois"
+Run at: 2022-11-01 06:00 UTC
+
+added: 3
+  - first.com (2022-10-30) https://whois.whoisxmlapi.com/lookup?q=first.com
+  - second.com (2022-10-30) https://whois.whoisxmlapi.com/lookup?q=second.com
+  ... and 1 more
+
+dropped: 1
+  - dropped.com (2022-10-30) https://whois.whoisxmlapi.com/lookup?q=dropped.com
+
+== include: "<lookup>" (0) ==
+No domains.
+`
+	if text.String() != wantText {
+		t.Errorf("RenderText() = %v, want %v", text.String(), wantText)
+	}
+
+	var html bytes.Buffer
+	if err := d.RenderHTML(&html); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`<h2>Whois brand (4)</h2>`,
+		`<li><a href="https://whois.whoisxmlapi.com/lookup?q=first.com">first.com</a> (2022-10-30)</li>`,
+		`<li>... and 1 more</li>`,
+		`<h2>include: &#34;&lt;lookup&gt;&#34; (0)</h2>`,
+	} {
+		if !strings.Contains(html.String(), want) {
+			t.Errorf("RenderHTML() does not contain %v", want)
+		}
+	}
+}
+
+// fakeSMTPServer accepts a single message and sends it to the channel.
+func fakeSMTPServer(t *testing.T) (string, <-chan string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := make(chan string, 1)
+
+	go func() {
+		defer l.Close()
+
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		tp := textproto.NewConn(conn)
+		_ = tp.PrintfLine("220 localhost ESMTP")
+
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+
+			switch cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); cmd {
+			case "EHLO", "HELO":
+				_ = tp.PrintfLine("250 localhost")
+			case "DATA":
+				_ = tp.PrintfLine("354 go ahead")
+				data, err := io.ReadAll(tp.DotReader())
+				if err != nil {
+					return
+				}
+				messages <- string(data)
+				_ = tp.PrintfLine("250 queued")
+			case "QUIT":
+				_ = tp.PrintfLine("221 bye")
+				return
+			default:
+				_ = tp.PrintfLine("250 OK")
+			}
+		}
+	}()
+
+	return l.Addr().String(), messages
+}
+
+// TestSMTPSender tests sending the digest to the local SMTP server.
+func TestSMTPSender(t *testing.T) {
+	addr, messages := fakeSMTPServer(t)
+
+	sender := &SMTPSender{Addr: addr, From: "alerts@example.com", To: []string{"team@example.com"}}
+
+	d := NewDigest(testSearchResults(), DigestOptions{Title: "Daily digest"})
+	if err := sender.Send(d); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := <-messages
+	for _, want := range []string{
+		"Subject: Daily digest (4 domains)\n",
+		"To: team@example.com\n",
+		"Content-Type: multipart/alternative; boundary=",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Type: text/html; charset=utf-8",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message does not contain %q", want)
+		}
+	}
+
+	decoded, err := io.ReadAll(quotedprintable.NewReader(bufio.NewReader(strings.NewReader(msg))))
+	if err != nil || !strings.Contains(string(decoded), "  - first.com (2022-10-30)") {
+		t.Errorf("message body = %s, err %v", decoded, err)
+	}
+
+	err = (&SMTPSender{Addr: addr}).Send(d)
+	checkErr(t, err, `invalid argument: "SMTPSender.From" is required.`)
+
+	err = (&SMTPSender{Addr: addr, From: "alerts@example.com", To: []string{"team@example.com\r\nBcc: all@example.com"}}).Send(d)
+	checkErr(t, err, `invalid argument: "SMTPSender.To.0" must be a valid email address.`)
+}
+
+// TestDigestMessageHeaders tests that the headers can't be injected.
+func TestDigestMessageHeaders(t *testing.T) {
+	d := NewDigest(testSearchResults(), DigestOptions{Title: "Daily\r\nBcc: all@example.com"})
+
+	msg, err := d.Message(`"Alerts, Inc." <alerts@example.com>`, []string{"Team <team@example.com>", "ops@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header := string(msg[:bytes.Index(msg, []byte("\r\n\r\n"))])
+	if strings.Contains(header, "\r\nBcc:") {
+		t.Errorf("header is injected: %q", header)
+	}
+	for _, want := range []string{
+		"From: \"Alerts, Inc.\" <alerts@example.com>\r\n",
+		"To: \"Team\" <team@example.com>, ops@example.com\r\n",
+		"Subject: =?utf-8?q?",
+	} {
+		if !strings.Contains(header, want) {
+			t.Errorf("header does not contain %q: %q", want, header)
+		}
+	}
+
+	_, err = d.Message("alerts@example.com\nBcc: all@example.com", nil)
+	checkErr(t, err, `invalid argument: "from" must be a valid email address.`)
+}
@@ -0,0 +1,181 @@
+package registrantalert
+
+import (
+	_ "embed" // The public suffix list snapshot.
+	"strings"
+	"sync"
+)
+
+// publicSuffixList is the snapshot of https://publicsuffix.org/list/public_suffix_list.dat.
+//
+//go:embed public_suffix_list.dat
+var publicSuffixList string
+
+// suffixRules is the parsed public suffix list. The rules are in the ASCII form.
+type suffixRules struct {
+	// rules maps the suffixes to true if they are in the ICANN section.
+	rules      map[string]bool
+	wildcards  map[string]bool
+	exceptions map[string]bool
+}
+
+var (
+	publicSuffixesOnce sync.Once
+	publicSuffixes     *suffixRules
+)
+
+// loadPublicSuffixes returns the parsed public suffix list.
+func loadPublicSuffixes() *suffixRules {
+	publicSuffixesOnce.Do(func() {
+		publicSuffixes = parsePublicSuffixList(publicSuffixList)
+	})
+	return publicSuffixes
+}
+
+// parsePublicSuffixList parses the list. The malformed rules are skipped.
+func parsePublicSuffixList(list string) *suffixRules {
+	s := &suffixRules{
+		rules:      make(map[string]bool),
+		wildcards:  make(map[string]bool),
+		exceptions: make(map[string]bool),
+	}
+
+	icann := false
+	for _, line := range strings.Split(list, "\n") {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(line, "// ===BEGIN ICANN DOMAINS==="):
+			icann = true
+			continue
+		case strings.HasPrefix(line, "// ===END ICANN DOMAINS==="):
+			icann = false
+			continue
+		case line == "" || strings.HasPrefix(line, "//"):
+			continue
+		}
+
+		rules := s.rules
+		switch {
+		case strings.HasPrefix(line, "!"):
+			rules, line = s.exceptions, line[1:]
+		case strings.HasPrefix(line, "*."):
+			rules, line = s.wildcards, line[2:]
+		}
+
+		ascii, err := toASCII(line)
+		if err != nil {
+			continue
+		}
+		rules[ascii] = icann
+	}
+
+	return s
+}
+
+// publicSuffix returns the public suffix of the ASCII domain name and whether
+// it is defined in the ICANN section. Unlisted top-level domains are public suffixes.
+func (s *suffixRules) publicSuffix(ascii string) (string, bool) {
+	labels := strings.Split(ascii, ".")
+
+	for i := range labels {
+		suffix := strings.Join(labels[i:], ".")
+
+		if icann, ok := s.exceptions[suffix]; ok {
+			return strings.Join(labels[i+1:], "."), icann
+		}
+		if icann, ok := s.rules[suffix]; ok {
+			return suffix, icann
+		}
+		if i+1 < len(labels) {
+			if icann, ok := s.wildcards[strings.Join(labels[i+1:], ".")]; ok {
+				return suffix, icann
+			}
+		}
+	}
+
+	return labels[len(labels)-1], false
+}
+
+// DomainInfo is the breakdown of the domain name.
+// The names are lowercase and have no trailing dot.
+type DomainInfo struct {
+	// ASCII is the domain name with Unicode labels encoded to Punycode.
+	ASCII string
+
+	// Unicode is the domain name with Punycode labels decoded.
+	Unicode string
+
+	// TLD is the top-level domain in the ASCII form.
+	TLD string
+
+	// PublicSuffix is the public suffix of the domain, e.g. "co.uk", in the ASCII form.
+	PublicSuffix string
+
+	// ICANN reports whether the public suffix is managed by ICANN, not by a private party.
+	ICANN bool
+
+	// RegistrableDomain is the public suffix with one more label, e.g. "example.co.uk",
+	// in the ASCII form. It's empty if the domain name is a public suffix itself.
+	RegistrableDomain string
+
+	// Labels is the number of labels of the domain name.
+	Labels int
+}
+
+// ParseDomainName returns the breakdown of the domain name using the embedded public suffix list.
+func ParseDomainName(name string) (DomainInfo, error) {
+	unicode, err := toUnicode(name)
+	if err != nil {
+		return DomainInfo{}, err
+	}
+
+	ascii, err := toASCII(unicode)
+	if err != nil {
+		return DomainInfo{}, err
+	}
+
+	labels := strings.Split(ascii, ".")
+	suffix, icann := loadPublicSuffixes().publicSuffix(ascii)
+
+	info := DomainInfo{
+		ASCII:        ascii,
+		Unicode:      unicode,
+		TLD:          labels[len(labels)-1],
+		PublicSuffix: suffix,
+		ICANN:        icann,
+		Labels:       len(labels),
+	}
+
+	if suffixLabels := strings.Count(suffix, ".") + 1; len(labels) > suffixLabels {
+		info.RegistrableDomain = strings.Join(labels[len(labels)-suffixLabels-1:], ".")
+	}
+
+	return info, nil
+}
+
+// Info returns the breakdown of the domain name.
+func (d DomainItem) Info() (DomainInfo, error) {
+	return ParseDomainName(d.DomainName)
+}
+
+// RegistrableDomain returns the registrable domain in the ASCII form,
+// or an empty string if the domain name is malformed or a public suffix.
+func (d DomainItem) RegistrableDomain() string {
+	info, _ := d.Info()
+	return info.RegistrableDomain
+}
+
+// PublicSuffix returns the public suffix in the ASCII form,
+// or an empty string if the domain name is malformed.
+func (d DomainItem) PublicSuffix() string {
+	info, _ := d.Info()
+	return info.PublicSuffix
+}
+
+// TLD returns the top-level domain in the ASCII form,
+// or an empty string if the domain name is malformed.
+func (d DomainItem) TLD() string {
+	info, _ := d.Info()
+	return info.TLD
+}
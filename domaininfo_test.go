@@ -0,0 +1,102 @@
+package registrantalert
+
+import (
+	"testing"
+)
+
+// TestParseDomainName tests the domain name breakdown.
+func TestParseDomainName(t *testing.T) {
+	tests := []struct {
+		name    string
+		domain  string
+		want    DomainInfo
+		wantErr string
+	}{
+		{
+			name:   "simple",
+			domain: "WhoisXMLAPI.com.",
+			want:   DomainInfo{"whoisxmlapi.com", "whoisxmlapi.com", "com", "com", true, "whoisxmlapi.com", 2},
+		},
+		{
+			name:   "multi-label suffix",
+			domain: "www.example.co.uk",
+			want:   DomainInfo{"www.example.co.uk", "www.example.co.uk", "uk", "co.uk", true, "example.co.uk", 4},
+		},
+		{
+			name:   "wildcard",
+			domain: "a.b.example.ck",
+			want:   DomainInfo{"a.b.example.ck", "a.b.example.ck", "ck", "example.ck", true, "b.example.ck", 4},
+		},
+		{
+			name:   "exception",
+			domain: "www.ck",
+			want:   DomainInfo{"www.ck", "www.ck", "ck", "ck", true, "www.ck", 2},
+		},
+		{
+			name:   "private suffix",
+			domain: "foo.github.io",
+			want:   DomainInfo{"foo.github.io", "foo.github.io", "io", "github.io", false, "foo.github.io", 3},
+		},
+		{
+			name:   "public suffix itself",
+			domain: "co.uk",
+			want:   DomainInfo{"co.uk", "co.uk", "uk", "co.uk", true, "", 2},
+		},
+		{
+			name:   "unlisted tld",
+			domain: "example.notatld",
+			want:   DomainInfo{"example.notatld", "example.notatld", "notatld", "notatld", false, "example.notatld", 2},
+		},
+		{
+			name:   "unicode",
+			domain: "Bücher.公司.cn",
+			want: DomainInfo{"xn--bcher-kva.xn--55qx5d.cn", "bücher.公司.cn", "cn",
+				"xn--55qx5d.cn", true, "xn--bcher-kva.xn--55qx5d.cn", 3},
+		},
+		{
+			name:   "punycode",
+			domain: "xn--80ak6aa92e.com",
+			want:   DomainInfo{"xn--80ak6aa92e.com", "аррӏе.com", "com", "com", true, "xn--80ak6aa92e.com", 2},
+		},
+		{
+			name:    "invalid punycode",
+			domain:  "xn--a-9.com",
+			wantErr: `invalid domain name "xn--a-9.com": punycode: invalid encoding`,
+		},
+		{
+			name:    "malformed",
+			domain:  "a..com",
+			wantErr: `invalid domain name "a..com": has an empty label`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDomainName(tt.domain)
+			checkErr(t, err, tt.wantErr)
+			if got != tt.want {
+				t.Errorf("ParseDomainName() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDomainItemInfo tests the enrichment helpers of DomainItem.
+func TestDomainItemInfo(t *testing.T) {
+	item := DomainItem{DomainName: "shop.example.com.au", Action: Added}
+
+	if got := item.RegistrableDomain(); got != "example.com.au" {
+		t.Errorf("RegistrableDomain() = %s", got)
+	}
+	if got := item.PublicSuffix(); got != "com.au" {
+		t.Errorf("PublicSuffix() = %s", got)
+	}
+	if got := item.TLD(); got != "au" {
+		t.Errorf("TLD() = %s", got)
+	}
+
+	malformed := DomainItem{DomainName: "-bad-.com"}
+	if got := malformed.RegistrableDomain(); got != "" {
+		t.Errorf("RegistrableDomain() = %s", got)
+	}
+}
@@ -48,8 +48,8 @@ func BasicPurchase(apikey string) {
 		registrantalert.OptionResponseFormat("XML"),
 		// this option results in domain names in the response will be encoded to Punycode
 		registrantalert.OptionPunycode(true),
-		// this option results in search through activities discovered since the given date
-		registrantalert.OptionSinceDate(time.Date(2022, 10, 27, 0, 0, 0, 0, time.UTC)))
+		// this option results in search through activities discovered in the last 3 days
+		registrantalert.OptionSinceLast(3*24*time.Hour))
 
 	if err != nil {
 		// Handle error message returned by server
@@ -137,8 +137,8 @@ func AdvancedPurchase(apikey string) {
 		[]registrantalert.AdvancedSearchTerm{{"RegistrantContact.Organization", "Airbnb, Inc.", true}},
 		// this option is ignored, as the inner parser works with JSON only
 		registrantalert.OptionResponseFormat("XML"),
-		// this option results in search through activities discovered since the given date
-		registrantalert.OptionSinceDate(time.Date(2022, 10, 31, 0, 0, 0, 0, time.UTC)))
+		// this option results in search through activities discovered in the last 1 day
+		registrantalert.OptionSinceLast(1*24*time.Hour))
 
 	if err != nil {
 		// Handle error message returned by server
@@ -169,8 +169,8 @@ func AdvancedRawData(apikey string) {
 	resp, err := client.AdvancedRawData(context.Background(),
 		// specify the including search terms
 		[]registrantalert.AdvancedSearchTerm{{"RegistrantContact.Organization", "Airbnb", false}},
-		// this option results in search through activities discovered since the given date
-		registrantalert.OptionSinceDate(time.Date(2022, 10, 01, 0, 0, 0, 0, time.UTC)),
+		// this option results in search through activities discovered in the last 30 days
+		registrantalert.OptionSinceLast(30*24*time.Hour),
 		// specify the domain-related dates to search through
 		registrantalert.OptionCreatedDateFrom(time.Date(2021, 01, 01, 0, 0, 0, 0, time.UTC)),
 		registrantalert.OptionCreatedDateTo(time.Date(2024, 01, 01, 0, 0, 0, 0, time.UTC)),
@@ -0,0 +1,371 @@
+package registrantalert
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Column is the DomainItem field exported as a CSV column.
+type Column string
+
+// List of exportable columns.
+const (
+	ColumnDomainName Column = "domainName"
+	ColumnAction     Column = "action"
+	ColumnDate       Column = "date"
+)
+
+// defaultColumns are the columns used when none are specified.
+var defaultColumns = []Column{
+	ColumnDomainName,
+	ColumnAction,
+	ColumnDate,
+}
+
+// CSVOptions configures the CSV encoding and decoding of DomainItem records.
+// The zero value works fine: all columns with the header, dates in YYYY-MM-DD format, comma-separated.
+type CSVOptions struct {
+	// Columns are the exported columns in the given order.
+	// When reading a file with the header, the columns are detected from the header.
+	Columns []Column
+
+	// OmitHeader disables writing the header line, or reading it when decoding.
+	OmitHeader bool
+
+	// DateFormat is the time.Format layout of the date column. Default: 2006-01-02.
+	DateFormat string
+
+	// Comma is the field delimiter. Default: ','.
+	Comma rune
+}
+
+// columns returns the validated columns.
+func (o CSVOptions) columns() ([]Column, error) {
+	if len(o.Columns) == 0 {
+		return defaultColumns, nil
+	}
+
+	for i, c := range o.Columns {
+		switch c {
+		case ColumnDomainName, ColumnAction, ColumnDate:
+		default:
+			return nil, &ArgError{"CSVOptions.Columns." + strconv.Itoa(i), "is unknown: " + string(c) + "."}
+		}
+	}
+
+	return o.Columns, nil
+}
+
+// dateFormat returns the layout of the date column.
+func (o CSVOptions) dateFormat() string {
+	if o.DateFormat == "" {
+		return dateFormat
+	}
+	return o.DateFormat
+}
+
+// CSVEncoder writes DomainItem records as CSV.
+type CSVEncoder struct {
+	w       *csv.Writer
+	opts    CSVOptions
+	columns []Column
+	err     error
+	started bool
+}
+
+// NewCSVEncoder creates CSVEncoder writing to w.
+// The records are buffered, call Flush after the last one.
+func NewCSVEncoder(w io.Writer, opts CSVOptions) *CSVEncoder {
+	e := &CSVEncoder{
+		w:    csv.NewWriter(w),
+		opts: opts,
+	}
+
+	if opts.Comma != 0 {
+		e.w.Comma = opts.Comma
+	}
+
+	e.columns, e.err = opts.columns()
+
+	return e
+}
+
+// WriteHeader writes the header line unless it is already written or disabled.
+// Encode writes the header automatically before the first record.
+func (e *CSVEncoder) WriteHeader() error {
+	if e.err != nil || e.started {
+		return e.err
+	}
+	e.started = true
+
+	if e.opts.OmitHeader {
+		return nil
+	}
+
+	header := make([]string, len(e.columns))
+	for i, c := range e.columns {
+		header[i] = string(c)
+	}
+
+	return e.write(header)
+}
+
+// Encode writes the record.
+func (e *CSVEncoder) Encode(item DomainItem) error {
+	if err := e.WriteHeader(); err != nil {
+		return err
+	}
+
+	record := make([]string, len(e.columns))
+	for i, c := range e.columns {
+		switch c {
+		case ColumnDomainName:
+			record[i] = item.DomainName
+		case ColumnAction:
+			record[i] = string(item.Action)
+		case ColumnDate:
+			if !item.Date.IsZero() {
+				record[i] = time.Time(item.Date).Format(e.opts.dateFormat())
+			}
+		}
+	}
+
+	return e.write(record)
+}
+
+// write writes the line and remembers the first error.
+func (e *CSVEncoder) write(record []string) error {
+	if err := e.w.Write(record); err != nil {
+		e.err = fmt.Errorf("cannot write csv: %w", err)
+	}
+	return e.err
+}
+
+// Flush writes the buffered records to the underlying writer.
+func (e *CSVEncoder) Flush() error {
+	if e.err != nil {
+		return e.err
+	}
+
+	e.w.Flush()
+	if err := e.w.Error(); err != nil {
+		e.err = fmt.Errorf("cannot write csv: %w", err)
+	}
+
+	return e.err
+}
+
+// WriteCSV writes the records as CSV. The header is written even if there are no records.
+func WriteCSV(w io.Writer, items []DomainItem, opts CSVOptions) error {
+	e := NewCSVEncoder(w, opts)
+
+	if err := e.WriteHeader(); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if err := e.Encode(item); err != nil {
+			return err
+		}
+	}
+
+	return e.Flush()
+}
+
+// CSVDecoder reads DomainItem records from CSV.
+type CSVDecoder struct {
+	r       *csv.Reader
+	opts    CSVOptions
+	columns []Column
+	err     error
+	started bool
+}
+
+// NewCSVDecoder creates CSVDecoder reading from r.
+func NewCSVDecoder(r io.Reader, opts CSVOptions) *CSVDecoder {
+	d := &CSVDecoder{
+		r:    csv.NewReader(r),
+		opts: opts,
+	}
+
+	if opts.Comma != 0 {
+		d.r.Comma = opts.Comma
+	}
+	d.r.FieldsPerRecord = -1
+	d.r.ReuseRecord = true
+
+	d.columns, d.err = opts.columns()
+
+	return d
+}
+
+// readHeader detects the columns from the header line.
+// Unknown header names are skipped.
+func (d *CSVDecoder) readHeader() error {
+	d.started = true
+
+	if d.opts.OmitHeader {
+		return nil
+	}
+
+	header, err := d.r.Read()
+	if err != nil {
+		if err == io.EOF {
+			return err
+		}
+		return fmt.Errorf("cannot read csv: %w", err)
+	}
+
+	d.columns = make([]Column, len(header))
+	for i, name := range header {
+		d.columns[i] = Column(name)
+	}
+
+	return nil
+}
+
+// Decode reads the next record. It returns io.EOF when there are no more records.
+func (d *CSVDecoder) Decode() (DomainItem, error) {
+	var item DomainItem
+
+	if d.err != nil {
+		return item, d.err
+	}
+
+	if !d.started {
+		if d.err = d.readHeader(); d.err != nil {
+			return item, d.err
+		}
+	}
+
+	record, err := d.r.Read()
+	if err != nil {
+		if err != io.EOF {
+			err = fmt.Errorf("cannot read csv: %w", err)
+		}
+		d.err = err
+		return item, err
+	}
+
+	for i, value := range record {
+		if i >= len(d.columns) {
+			break
+		}
+
+		switch d.columns[i] {
+		case ColumnDomainName:
+			item.DomainName = value
+		case ColumnAction:
+			item.Action = normalizeAction(value)
+		case ColumnDate:
+			if value == "" {
+				continue
+			}
+			date, err := time.Parse(d.opts.dateFormat(), value)
+			if err != nil {
+				line, _ := d.r.FieldPos(i)
+				return item, fmt.Errorf("cannot read csv: line %d: %w", line, err)
+			}
+			item.Date = Time(date)
+		}
+	}
+
+	return item, nil
+}
+
+// ReadCSV reads all CSV records.
+func ReadCSV(r io.Reader, opts CSVOptions) ([]DomainItem, error) {
+	d := NewCSVDecoder(r, opts)
+
+	var items []DomainItem
+	for {
+		item, err := d.Decode()
+		if errors.Is(err, io.EOF) {
+			return items, nil
+		}
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+}
+
+// NDJSONEncoder writes DomainItem records as newline-delimited JSON.
+type NDJSONEncoder struct {
+	enc *json.Encoder
+}
+
+// NewNDJSONEncoder creates NDJSONEncoder writing to w. Every record is written immediately.
+func NewNDJSONEncoder(w io.Writer) *NDJSONEncoder {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	return &NDJSONEncoder{enc: enc}
+}
+
+// Encode writes the record followed by a newline.
+func (e *NDJSONEncoder) Encode(item DomainItem) error {
+	if err := e.enc.Encode(item); err != nil {
+		return fmt.Errorf("cannot write ndjson: %w", err)
+	}
+	return nil
+}
+
+// WriteNDJSON writes the records as newline-delimited JSON.
+func WriteNDJSON(w io.Writer, items []DomainItem) error {
+	e := NewNDJSONEncoder(w)
+
+	for _, item := range items {
+		if err := e.Encode(item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NDJSONDecoder reads DomainItem records from newline-delimited JSON.
+type NDJSONDecoder struct {
+	dec *json.Decoder
+}
+
+// NewNDJSONDecoder creates NDJSONDecoder reading from r.
+func NewNDJSONDecoder(r io.Reader) *NDJSONDecoder {
+	return &NDJSONDecoder{dec: json.NewDecoder(r)}
+}
+
+// Decode reads the next record. It returns io.EOF when there are no more records.
+func (d *NDJSONDecoder) Decode() (DomainItem, error) {
+	var item DomainItem
+
+	if err := d.dec.Decode(&item); err != nil {
+		if err == io.EOF {
+			return item, err
+		}
+		return item, fmt.Errorf("cannot read ndjson: %w", err)
+	}
+
+	return item, nil
+}
+
+// ReadNDJSON reads all newline-delimited JSON records.
+func ReadNDJSON(r io.Reader) ([]DomainItem, error) {
+	d := NewNDJSONDecoder(r)
+
+	var items []DomainItem
+	for {
+		item, err := d.Decode()
+		if errors.Is(err, io.EOF) {
+			return items, nil
+		}
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+}
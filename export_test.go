@@ -0,0 +1,114 @@
+package registrantalert
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testDomainItems returns the sample of DomainItem records for testing.
+func testDomainItems() []DomainItem {
+	return []DomainItem{
+		{"batchwhois.com", Discovered, Time(time.Date(2022, 10, 30, 0, 0, 0, 0, time.UTC))},
+		{"whois,lookup.info", Updated, Time(time.Date(2022, 10, 29, 0, 0, 0, 0, time.UTC))},
+		{"whoisdodster.com", Added, emptyTime},
+	}
+}
+
+// TestCSV tests the CSV encoding and decoding.
+func TestCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    CSVOptions
+		want    string
+		wantErr string
+	}{
+		{
+			name: "default options",
+			opts: CSVOptions{},
+			want: "domainName,action,date\nbatchwhois.com,discovered,2022-10-30\n" +
+				"\"whois,lookup.info\",updated,2022-10-29\nwhoisdodster.com,added,\n",
+		},
+		{
+			name: "custom columns and date format",
+			opts: CSVOptions{
+				Columns:    []Column{ColumnDate, ColumnDomainName, ColumnAction},
+				OmitHeader: true,
+				DateFormat: "02.01.2006",
+				Comma:      ';',
+			},
+			want: "30.10.2022;batchwhois.com;discovered\n29.10.2022;whois,lookup.info;updated\n;whoisdodster.com;added\n",
+		},
+		{
+			name:    "unknown column",
+			opts:    CSVOptions{Columns: []Column{ColumnDomainName, "registrant"}},
+			wantErr: `invalid argument: "CSVOptions.Columns.1" is unknown: registrant.`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+
+			err := WriteCSV(&b, testDomainItems(), tt.opts)
+			checkErr(t, err, tt.wantErr)
+			if tt.wantErr != "" {
+				return
+			}
+
+			if b.String() != tt.want {
+				t.Errorf("WriteCSV() = %q, want %q", b.String(), tt.want)
+			}
+
+			items, err := ReadCSV(&b, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(items, testDomainItems()) {
+				t.Errorf("ReadCSV() = %v, want %v", items, testDomainItems())
+			}
+		})
+	}
+}
+
+// TestReadCSVHeader tests that the columns are detected from the header.
+func TestReadCSVHeader(t *testing.T) {
+	const data = "comment,date,domainName\nfirst,2022-10-30,batchwhois.com\nsecond,bad date,whois.com\n"
+
+	items, err := ReadCSV(strings.NewReader(data), CSVOptions{})
+	checkErr(t, err, `cannot read csv: line 3: parsing time "bad date" as "2006-01-02": cannot parse "bad date" as "2006"`)
+
+	want := []DomainItem{{DomainName: "batchwhois.com", Date: Time(time.Date(2022, 10, 30, 0, 0, 0, 0, time.UTC))}}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("ReadCSV() = %v, want %v", items, want)
+	}
+}
+
+// TestNDJSON tests the newline-delimited JSON encoding and decoding.
+func TestNDJSON(t *testing.T) {
+	var b bytes.Buffer
+
+	if err := WriteNDJSON(&b, testDomainItems()); err != nil {
+		t.Fatal(err)
+	}
+
+	const want = `{"domainName":"batchwhois.com","action":"discovered","date":"2022-10-30"}
+{"domainName":"whois,lookup.info","action":"updated","date":"2022-10-29"}
+{"domainName":"whoisdodster.com","action":"added","date":""}
+`
+	if b.String() != want {
+		t.Errorf("WriteNDJSON() = %v, want %v", b.String(), want)
+	}
+
+	items, err := ReadNDJSON(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(items, testDomainItems()) {
+		t.Errorf("ReadNDJSON() = %v, want %v", items, testDomainItems())
+	}
+
+	_, err = ReadNDJSON(strings.NewReader(`{"domainName":"whois.com"}` + "\n{"))
+	checkErr(t, err, "cannot read ndjson: unexpected EOF")
+}
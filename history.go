@@ -0,0 +1,461 @@
+package registrantalert
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HistoryRecord is the domain event seen in a search run.
+type HistoryRecord struct {
+	// Search is the name of the search or the description of its terms.
+	Search string `json:"search"`
+
+	// RunAt is the time of the search run.
+	RunAt time.Time `json:"runAt"`
+
+	// DomainName is the full domain name.
+	DomainName string `json:"domainName"`
+
+	// Action is the related action.
+	Action Action `json:"action"`
+
+	// Date is the event date.
+	Date Time `json:"date"`
+}
+
+// key returns the identity of the record used to skip duplicates.
+func (r HistoryRecord) key() string {
+	return r.Search + "|" + r.RunAt.UTC().Format(time.RFC3339Nano) + "|" + historyDomainKey(r.DomainName) +
+		"|" + string(r.Action) + "|" + r.Date.String()
+}
+
+// historyDomainKey returns the index key of the domain name.
+func historyDomainKey(name string) string {
+	if ascii, err := toASCII(name); err == nil {
+		return ascii
+	}
+	return strings.ToLower(name)
+}
+
+// HistoryQuery selects the history records. Empty fields are not checked.
+type HistoryQuery struct {
+	// DomainName is the domain name, matched case-insensitively in the Unicode or ASCII form.
+	DomainName string
+
+	// Search is the name of the search as in HistoryRecord.Search.
+	Search string
+
+	// Action is the event action.
+	Action Action
+
+	// From and To limit the event dates inclusive.
+	From time.Time
+	To   time.Time
+}
+
+// errHistoryClosed is returned when the closed history is modified.
+var errHistoryClosed = errors.New("history is closed")
+
+// historyFile is the history file opened for appending.
+type historyFile interface {
+	io.WriteCloser
+	Stat() (os.FileInfo, error)
+	Truncate(size int64) error
+	Sync() error
+}
+
+// History is the file-based store of the domain events seen across search runs.
+// The records are appended to the file as newline-delimited JSON and indexed in memory.
+// It's safe for concurrent use.
+type History struct {
+	path string
+
+	mu       sync.RWMutex
+	file     historyFile
+	records  []HistoryRecord
+	keys     map[string]struct{}
+	byDomain map[string][]int
+	bySearch map[string][]int
+	byAction map[Action][]int
+	byDate   map[string][]int
+}
+
+// OpenHistory opens the history file, creating it if it does not exist, and loads its records.
+func OpenHistory(path string) (*History, error) {
+	h := &History{path: path}
+
+	if err := h.load(); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open history: %w", err)
+	}
+	h.file = file
+
+	return h, nil
+}
+
+// load reads the records of the history file.
+// The broken last line left by an interrupted write is skipped and cut off the file.
+func (h *History) load() error {
+	h.reset()
+
+	f, err := os.Open(h.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cannot open history: %w", err)
+	}
+	defer f.Close()
+
+	var size, valid int64
+	var broken error
+
+	// The offsets are counted in raw bytes, so the lines may end with CRLF.
+	reader := bufio.NewReader(f)
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		size += int64(len(data))
+
+		if len(bytes.TrimSpace(data)) != 0 {
+			if broken != nil {
+				return broken
+			}
+
+			var record HistoryRecord
+			if uerr := json.Unmarshal(data, &record); uerr != nil {
+				broken = fmt.Errorf("cannot read history: line %d: %w", line, uerr)
+			} else {
+				h.index(record)
+				valid = size
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("cannot read history: %w", err)
+		}
+	}
+
+	if broken != nil {
+		if err := os.Truncate(h.path, valid); err != nil {
+			return fmt.Errorf("cannot repair history: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// reset clears the records and the indexes.
+func (h *History) reset() {
+	h.records = nil
+	h.keys = make(map[string]struct{})
+	h.byDomain = make(map[string][]int)
+	h.bySearch = make(map[string][]int)
+	h.byAction = make(map[Action][]int)
+	h.byDate = make(map[string][]int)
+}
+
+// index adds the record to the indexes. It reports false if the record is a duplicate.
+func (h *History) index(record HistoryRecord) bool {
+	key := record.key()
+	if _, ok := h.keys[key]; ok {
+		return false
+	}
+	h.keys[key] = struct{}{}
+
+	i := len(h.records)
+	h.records = append(h.records, record)
+
+	domain := historyDomainKey(record.DomainName)
+	h.byDomain[domain] = append(h.byDomain[domain], i)
+	h.bySearch[record.Search] = append(h.bySearch[record.Search], i)
+	h.byAction[record.Action] = append(h.byAction[record.Action], i)
+	if day := KeyDay(DomainItem{Date: record.Date}); day != "" {
+		h.byDate[day] = append(h.byDate[day], i)
+	}
+
+	return true
+}
+
+// Add stores the domains of the search results. Results without RunAt are recorded at the current time.
+// Records already stored for the same search run are skipped.
+// The records are indexed only after they are written, so a failed call can be retried.
+func (h *History) Add(results ...SearchResult) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.file == nil {
+		return errHistoryClosed
+	}
+
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+
+	var pending []HistoryRecord
+	seen := make(map[string]struct{})
+	for _, result := range results {
+		runAt := result.RunAt
+		if runAt.IsZero() {
+			runAt = time.Now()
+		}
+
+		for _, item := range result.domains() {
+			record := HistoryRecord{
+				Search:     result.Search.String(),
+				RunAt:      runAt,
+				DomainName: item.DomainName,
+				Action:     item.Action,
+				Date:       item.Date,
+			}
+
+			key := record.key()
+			if _, ok := h.keys[key]; ok {
+				continue
+			}
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			if err := enc.Encode(record); err != nil {
+				return fmt.Errorf("cannot write history: %w", err)
+			}
+			pending = append(pending, record)
+		}
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	info, err := h.file.Stat()
+	if err != nil {
+		return fmt.Errorf("cannot write history: %w", err)
+	}
+
+	// A partial line would break the next records, so the file is cut back on failure.
+	if _, err := h.file.Write(b.Bytes()); err != nil {
+		if terr := h.file.Truncate(info.Size()); terr != nil {
+			return fmt.Errorf("cannot write history: %w; cannot truncate history: %v", err, terr)
+		}
+		return fmt.Errorf("cannot write history: %w", err)
+	}
+	if err := h.file.Sync(); err != nil {
+		return fmt.Errorf("cannot write history: %w", err)
+	}
+
+	for _, record := range pending {
+		h.index(record)
+	}
+
+	return nil
+}
+
+// Query returns the matching records sorted by the run time, then by the domain name.
+func (h *History) Query(q HistoryQuery) []HistoryRecord {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var fromDay, toDay string
+	if !q.From.IsZero() {
+		fromDay = q.From.Format(dateFormat)
+	}
+	if !q.To.IsZero() {
+		toDay = q.To.Format(dateFormat)
+	}
+
+	var candidates []int
+	switch {
+	case q.DomainName != "":
+		candidates = h.byDomain[historyDomainKey(q.DomainName)]
+	case q.Search != "":
+		candidates = h.bySearch[q.Search]
+	case q.Action != "":
+		candidates = h.byAction[q.Action]
+	case fromDay != "" || toDay != "":
+		for day, indexes := range h.byDate {
+			if (fromDay == "" || day >= fromDay) && (toDay == "" || day <= toDay) {
+				candidates = append(candidates, indexes...)
+			}
+		}
+		sort.Ints(candidates)
+	default:
+		candidates = make([]int, len(h.records))
+		for i := range candidates {
+			candidates[i] = i
+		}
+	}
+
+	records := []HistoryRecord{}
+	for _, i := range candidates {
+		r := h.records[i]
+
+		if q.Search != "" && r.Search != q.Search || q.Action != "" && r.Action != q.Action {
+			continue
+		}
+		if fromDay != "" || toDay != "" {
+			day := KeyDay(DomainItem{Date: r.Date})
+			if day == "" || fromDay != "" && day < fromDay || toDay != "" && day > toDay {
+				continue
+			}
+		}
+
+		records = append(records, r)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		if !records[i].RunAt.Equal(records[j].RunAt) {
+			return records[i].RunAt.Before(records[j].RunAt)
+		}
+		return records[i].DomainName < records[j].DomainName
+	})
+
+	return records
+}
+
+// Timeline returns the records of the domain in the order they were seen.
+func (h *History) Timeline(domainName string) []HistoryRecord {
+	return h.Query(HistoryQuery{DomainName: domainName})
+}
+
+// SearchTimeline returns the records of the search in the order they were seen.
+func (h *History) SearchTimeline(search string) []HistoryRecord {
+	return h.Query(HistoryQuery{Search: search})
+}
+
+// FirstSeen returns the earliest record of the domain.
+func (h *History) FirstSeen(domainName string) (HistoryRecord, bool) {
+	records := h.Timeline(domainName)
+	if len(records) == 0 {
+		return HistoryRecord{}, false
+	}
+	return records[0], true
+}
+
+// LastSeen returns the latest record of the domain.
+func (h *History) LastSeen(domainName string) (HistoryRecord, bool) {
+	records := h.Timeline(domainName)
+	if len(records) == 0 {
+		return HistoryRecord{}, false
+	}
+	return records[len(records)-1], true
+}
+
+// Len returns the number of records.
+func (h *History) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.records)
+}
+
+// Prune removes the records of the runs before the time and rewrites the file.
+// It returns the number of removed records. If the rewritten file cannot be reopened, the history is closed.
+func (h *History) Prune(before time.Time) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var kept []HistoryRecord
+	for _, r := range h.records {
+		if !r.RunAt.Before(before) {
+			kept = append(kept, r)
+		}
+	}
+
+	removed := len(h.records) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+	if h.file == nil {
+		return 0, errHistoryClosed
+	}
+
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	for _, r := range kept {
+		if err := enc.Encode(r); err != nil {
+			return 0, fmt.Errorf("cannot prune history: %w", err)
+		}
+	}
+
+	tmp := h.path + ".tmp"
+	if err := writeFileSync(tmp, b.Bytes()); err != nil {
+		return 0, fmt.Errorf("cannot prune history: %w", err)
+	}
+
+	// The file is unusable once closed, so any failure below closes the history.
+	err := h.file.Close()
+	h.file = nil
+	if err == nil {
+		err = os.Rename(tmp, h.path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return 0, fmt.Errorf("cannot prune history: %w; %v", err, errHistoryClosed)
+	}
+
+	file, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("cannot prune history: %w; %v", err, errHistoryClosed)
+	}
+	h.file = file
+
+	h.reset()
+	for _, r := range kept {
+		h.index(r)
+	}
+
+	return removed, nil
+}
+
+// writeFileSync writes the file and flushes it to the disk.
+func writeFileSync(path string, data []byte) (err error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	if _, err = f.Write(data); err != nil {
+		return err
+	}
+
+	return f.Sync()
+}
+
+// Close closes the history file.
+func (h *History) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.file == nil {
+		return nil
+	}
+
+	err := h.file.Close()
+	h.file = nil
+
+	return err
+}
@@ -0,0 +1,333 @@
+package registrantalert
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestHistory tests the ingestion, the queries, the persistence and the pruning.
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.ndjson")
+
+	h, err := OpenHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	day := func(d int) Time {
+		return Time(time.Date(2022, 10, d, 0, 0, 0, 0, time.UTC))
+	}
+	week1 := time.Date(2022, 10, 24, 8, 0, 0, 0, time.UTC)
+	week2 := week1.AddDate(0, 0, 7)
+
+	brand := Search{Name: "brand"}
+	other := Search{Name: "other"}
+
+	err = h.Add(
+		SearchResult{Search: brand, RunAt: week1, Response: withItems([]DomainItem{
+			{"whoisxmlapi.net", Added, day(22)},
+			{"bücher.de", Discovered, day(23)},
+		})},
+		SearchResult{Search: other, RunAt: week1, Response: withItems([]DomainItem{
+			{"whoisxmlapi.net", Added, day(22)},
+		})},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The repeated run is ignored.
+	err = h.Add(SearchResult{Search: brand, RunAt: week1, Response: withItems([]DomainItem{
+		{"whoisxmlapi.net", Added, day(22)},
+	})})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = h.Add(SearchResult{Search: brand, RunAt: week2, Response: withItems([]DomainItem{
+		{"WhoisXMLAPI.net", Dropped, day(30)},
+	})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	h, err = OpenHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	if h.Len() != 4 {
+		t.Fatalf("Len() = %d, want 4", h.Len())
+	}
+
+	first, ok := h.FirstSeen("whoisxmlapi.net")
+	if !ok || !first.RunAt.Equal(week1) || first.Action != Added {
+		t.Errorf("FirstSeen() = %+v, %v", first, ok)
+	}
+	last, ok := h.LastSeen("WHOISXMLAPI.NET")
+	if !ok || !last.RunAt.Equal(week2) || last.Action != Dropped {
+		t.Errorf("LastSeen() = %+v, %v", last, ok)
+	}
+	if _, ok := h.FirstSeen("example.com"); ok {
+		t.Error("FirstSeen() found unknown domain")
+	}
+
+	if records := h.Timeline("xn--bcher-kva.de"); len(records) != 1 || records[0].DomainName != "bücher.de" {
+		t.Errorf("Timeline() = %+v", records)
+	}
+
+	tests := []struct {
+		name  string
+		query HistoryQuery
+		want  []string
+	}{
+		{"search", HistoryQuery{Search: "brand"}, []string{"bücher.de", "whoisxmlapi.net", "WhoisXMLAPI.net"}},
+		{"action", HistoryQuery{Action: Added}, []string{"whoisxmlapi.net", "whoisxmlapi.net"}},
+		{"search and action", HistoryQuery{Search: "other", Action: Added}, []string{"whoisxmlapi.net"}},
+		{"date range", HistoryQuery{From: time.Date(2022, 10, 23, 0, 0, 0, 0, time.UTC), To: time.Date(2022, 10, 29, 0, 0, 0, 0, time.UTC)},
+			[]string{"bücher.de"}},
+		{"from date", HistoryQuery{From: time.Date(2022, 10, 30, 0, 0, 0, 0, time.UTC)}, []string{"WhoisXMLAPI.net"}},
+		{"date range and action", HistoryQuery{Action: Added, To: time.Date(2022, 10, 22, 0, 0, 0, 0, time.UTC)},
+			[]string{"whoisxmlapi.net", "whoisxmlapi.net"}},
+		{"no match", HistoryQuery{Search: "missing"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, r := range h.Query(tt.query) {
+				got = append(got, r.DomainName)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Query() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	removed, err := h.Prune(week2)
+	if err != nil || removed != 3 {
+		t.Fatalf("Prune() = %d, %v", removed, err)
+	}
+	if h.Len() != 1 || len(h.SearchTimeline("other")) != 0 {
+		t.Errorf("records after pruning = %+v", h.Query(HistoryQuery{}))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 1 {
+		t.Errorf("history file has %d lines, want 1", lines)
+	}
+
+	// Records added after pruning go to the rewritten file.
+	if err := h.Add(SearchResult{Search: other, RunAt: week2, Response: withItems([]DomainItem{{"example.com", Added, day(31)}})}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("history file has %d lines, want 2", lines)
+	}
+}
+
+// TestHistoryAddRetry tests that the records of a failed write are not indexed and can be added again.
+func TestHistoryAddRetry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.ndjson")
+
+	h, err := OpenHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	result := SearchResult{Search: Search{Name: "brand"}, RunAt: time.Date(2022, 10, 24, 8, 0, 0, 0, time.UTC),
+		Response: withItems([]DomainItem{{"whoisxmlapi.net", Added, emptyTime}, {"whoisxmlapi.net", Added, emptyTime}})}
+
+	// The read-only file fails the write.
+	writable := h.file
+	if h.file, err = os.Open(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Add(result); err == nil || !strings.HasPrefix(err.Error(), "cannot write history: ") {
+		t.Fatalf("Add() error = %v", err)
+	}
+	_ = h.file.Close()
+	h.file = writable
+
+	if h.Len() != 0 {
+		t.Fatalf("Len() after failed write = %d, want 0", h.Len())
+	}
+
+	if err := h.Add(result); err != nil {
+		t.Fatal(err)
+	}
+	if h.Len() != 1 {
+		t.Errorf("Len() after retry = %d, want 1", h.Len())
+	}
+
+	data, _ := os.ReadFile(path)
+	if lines := strings.Count(string(data), "\n"); lines != 1 {
+		t.Errorf("history file has %d lines, want 1", lines)
+	}
+}
+
+// TestHistoryPruneFailure tests that the history is closed when the pruned file cannot be replaced.
+func TestHistoryPruneFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.ndjson")
+
+	h, err := OpenHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	runAt := time.Date(2022, 10, 24, 8, 0, 0, 0, time.UTC)
+	if err := h.Add(SearchResult{Search: Search{Name: "brand"}, RunAt: runAt,
+		Response: withItems([]DomainItem{{"whoisxmlapi.net", Added, emptyTime}})}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The non-empty directory in place of the file fails the rename.
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(path, "dir"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = h.Prune(runAt.Add(time.Hour))
+	if err == nil || !strings.HasPrefix(err.Error(), "cannot prune history: ") || !strings.HasSuffix(err.Error(), "; history is closed") {
+		t.Fatalf("Prune() error = %v", err)
+	}
+
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file is left, Stat() error = %v", err)
+	}
+
+	err = h.Add(SearchResult{Search: Search{Name: "brand"}, RunAt: runAt})
+	checkErr(t, err, errHistoryClosed.Error())
+}
+
+// shortWriteFile is the history file which writes only a half of the data.
+type shortWriteFile struct {
+	*os.File
+}
+
+// Write writes the first half of the data and fails.
+func (f shortWriteFile) Write(b []byte) (int, error) {
+	n, _ := f.File.Write(b[:len(b)/2])
+	return n, io.ErrShortWrite
+}
+
+// TestHistoryShortWrite tests that a partial write is cut off the file.
+func TestHistoryShortWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.ndjson")
+
+	h, err := OpenHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	runAt := time.Date(2022, 10, 24, 8, 0, 0, 0, time.UTC)
+	first := SearchResult{Search: Search{Name: "brand"}, RunAt: runAt,
+		Response: withItems([]DomainItem{{"whoisxmlapi.net", Added, emptyTime}})}
+	second := SearchResult{Search: Search{Name: "brand"}, RunAt: runAt.Add(time.Hour),
+		Response: withItems([]DomainItem{{"whoisxmlapi.org", Added, emptyTime}})}
+
+	if err := h.Add(first); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := os.ReadFile(path)
+
+	h.file = shortWriteFile{h.file.(*os.File)}
+	if err := h.Add(second); err == nil || !strings.HasPrefix(err.Error(), "cannot write history: ") {
+		t.Fatalf("Add() error = %v", err)
+	}
+	h.file = h.file.(shortWriteFile).File
+
+	if after, _ := os.ReadFile(path); string(after) != string(before) {
+		t.Fatalf("history file after failed write = %q, want %q", after, before)
+	}
+
+	if err := h.Add(second); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := OpenHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+
+	if reopened.Len() != 2 {
+		t.Errorf("Len() after reopening = %d, want 2", reopened.Len())
+	}
+}
+
+// TestOpenHistoryBrokenLastLine tests that the broken last line is skipped and cut off the file.
+func TestOpenHistoryBrokenLastLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.ndjson")
+	valid := "{\"search\":\"a\",\"domainName\":\"a.com\"}\n"
+	if err := os.WriteFile(path, []byte(valid+"{\"search\":\"b\",\"dom"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := OpenHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	if h.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", h.Len())
+	}
+	if data, _ := os.ReadFile(path); string(data) != valid {
+		t.Errorf("history file = %q, want %q", data, valid)
+	}
+}
+
+// TestOpenHistoryCRLF tests that the broken last line is cut off the file with CRLF line endings.
+func TestOpenHistoryCRLF(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.ndjson")
+	valid := "{\"search\":\"a\",\"domainName\":\"a.com\"}\r\n\r\n{\"search\":\"b\",\"domainName\":\"b.com\"}\r\n"
+	if err := os.WriteFile(path, []byte(valid+"{\"search\":\"c\",\"dom"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := OpenHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	if h.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", h.Len())
+	}
+	if data, _ := os.ReadFile(path); string(data) != valid {
+		t.Errorf("history file = %q, want %q", data, valid)
+	}
+}
+
+// TestOpenHistoryMalformed tests the error of the malformed history file.
+func TestOpenHistoryMalformed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.ndjson")
+	if err := os.WriteFile(path, []byte("{\"search\":\"a\"}\n\nnot json\n{\"search\":\"b\"}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := OpenHistory(path)
+	checkErr(t, err, "cannot read history: line 3: invalid character 'o' in literal null (expecting 'u')")
+}
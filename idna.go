@@ -0,0 +1,198 @@
+package registrantalert
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ToASCII converts the domain name to the lowercase ASCII form, encoding Unicode labels to Punycode.
+// Unlike OptionPunycode it works locally on any name, e.g. before matching against ASCII blocklists.
+// Fullwidth characters and Latin ligatures are mapped as by UTS #46, e.g. "Ｅxample.com" becomes "example.com".
+// Names that are not in Unicode NFC form or have halfwidth forms are rejected, as the library
+// can't normalize them without the Unicode tables; normalize such input first, e.g. with golang.org/x/text.
+// The invisible joiners ZWJ and ZWNJ are rejected outside of the context allowed by IDNA2008,
+// as are the control, unassigned, space, punctuation and symbol characters.
+func ToASCII(name string) (string, error) {
+	return toASCII(name)
+}
+
+// ToUnicode converts the domain name to the lowercase Unicode form, decoding Punycode labels.
+// The decoded labels are checked as by ToASCII.
+func ToUnicode(name string) (string, error) {
+	return toUnicode(name)
+}
+
+// ASCIIName returns the domain name in the ASCII (Punycode) form.
+func (d DomainItem) ASCIIName() (string, error) {
+	return toASCII(d.DomainName)
+}
+
+// UnicodeName returns the domain name in the readable Unicode form.
+func (d DomainItem) UnicodeName() (string, error) {
+	return toUnicode(d.DomainName)
+}
+
+// confusables maps the characters commonly used to imitate Latin letters to these letters.
+var confusables = map[rune]rune{
+	// Cyrillic.
+	'а': 'a', 'в': 'b', 'с': 'c', 'ԁ': 'd', 'е': 'e', 'ё': 'e', 'һ': 'h', 'і': 'i', 'ї': 'i', 'ј': 'j',
+	'к': 'k', 'ӏ': 'l', 'м': 'm', 'н': 'h', 'о': 'o', 'р': 'p', 'ԛ': 'q', 'ѕ': 's', 'т': 't', 'у': 'y',
+	'ԝ': 'w', 'х': 'x', 'ү': 'y',
+	// Greek.
+	'α': 'a', 'β': 'b', 'ε': 'e', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o', 'ρ': 'p', 'τ': 't', 'υ': 'u',
+	'χ': 'x', 'ϲ': 'c', 'ϳ': 'j',
+	// Latin look-alikes.
+	'ı': 'i', 'ȷ': 'j', 'ℓ': 'l', 'ɩ': 'i', 'ɑ': 'a', 'ɡ': 'g',
+}
+
+// allowedScriptSets are the combinations of scripts allowed in a single label,
+// following the "Highly Restrictive" level of Unicode Technical Standard #39.
+var allowedScriptSets = [][]string{
+	{"Latin", "Han", "Hiragana", "Katakana"},
+	{"Latin", "Han", "Bopomofo"},
+	{"Latin", "Han", "Hangul"},
+}
+
+// IDNReport is the result of the internationalized domain name inspection.
+type IDNReport struct {
+	// ASCII is the domain name in the ASCII (Punycode) form.
+	ASCII string
+
+	// Unicode is the domain name in the Unicode form.
+	Unicode string
+
+	// Scripts are the sorted names of the Unicode scripts used in the domain name.
+	// Common characters like digits and hyphens are not counted.
+	Scripts []string
+
+	// MixedScript reports whether a label mixes scripts not normally used together, e.g. Latin and Cyrillic.
+	MixedScript bool
+
+	// Confusable reports whether a label imitates Latin letters with characters of other scripts.
+	Confusable bool
+
+	// Skeleton is the Unicode form with the confusable characters replaced by the Latin letters
+	// they imitate, e.g. "apple.com" for "аррӏе.com". Compare it with the protected names.
+	Skeleton string
+}
+
+// Suspicious reports whether the domain name is likely to imitate another one.
+func (r IDNReport) Suspicious() bool {
+	return r.MixedScript || r.Confusable
+}
+
+// runeScript returns the name of the script of the character,
+// or an empty string for the common and inherited characters.
+func runeScript(r rune) string {
+	if r < utf8.RuneSelf {
+		if unicode.IsLetter(r) {
+			return "Latin"
+		}
+		return ""
+	}
+	if unicode.Is(unicode.Common, r) || unicode.Is(unicode.Inherited, r) {
+		return ""
+	}
+
+	for name, table := range unicode.Scripts {
+		if unicode.Is(table, r) {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// allowedScripts reports whether the scripts can be used together in a label.
+func allowedScripts(scripts map[string]bool) bool {
+	if len(scripts) <= 1 {
+		return true
+	}
+
+	for _, set := range allowedScriptSets {
+		allowed := true
+		for script := range scripts {
+			if !containsString(set, script) {
+				allowed = false
+				break
+			}
+		}
+		if allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// containsString reports whether the list contains the string.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// InspectIDN converts the domain name to both forms and detects mixed scripts and confusable characters.
+func InspectIDN(name string) (IDNReport, error) {
+	unicodeName, err := toUnicode(name)
+	if err != nil {
+		return IDNReport{}, err
+	}
+
+	asciiName, err := toASCII(unicodeName)
+	if err != nil {
+		return IDNReport{}, err
+	}
+
+	report := IDNReport{ASCII: asciiName, Unicode: unicodeName}
+
+	allScripts := make(map[string]bool)
+	labels := strings.Split(unicodeName, ".")
+	for i, label := range labels {
+		scripts := make(map[string]bool)
+		for _, r := range label {
+			if script := runeScript(r); script != "" {
+				scripts[script] = true
+				allScripts[script] = true
+			}
+		}
+
+		if !allowedScripts(scripts) {
+			report.MixedScript = true
+		}
+
+		hasConfusable := false
+		labels[i] = strings.Map(func(r rune) rune {
+			if latin, ok := confusables[r]; ok {
+				hasConfusable = true
+				return latin
+			}
+			return r
+		}, label)
+
+		// The confusable characters of the native words are fine, e.g. in "пример",
+		// unless the whole label can be spelled with Latin letters or mixes with them.
+		if hasConfusable && (isASCII(labels[i]) || scripts["Latin"]) {
+			report.Confusable = true
+		}
+	}
+
+	for script := range allScripts {
+		report.Scripts = append(report.Scripts, script)
+	}
+	sort.Strings(report.Scripts)
+
+	report.Skeleton = strings.Join(labels, ".")
+
+	return report, nil
+}
+
+// InspectIDN inspects the domain name for mixed scripts and confusable characters.
+func (d DomainItem) InspectIDN() (IDNReport, error) {
+	return InspectIDN(d.DomainName)
+}
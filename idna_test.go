@@ -0,0 +1,135 @@
+package registrantalert
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestIDNConversion tests the conversion between the ASCII and Unicode forms.
+func TestIDNConversion(t *testing.T) {
+	tests := []struct {
+		unicode string
+		ascii   string
+	}{
+		{"whoisxmlapi.com", "whoisxmlapi.com"},
+		{"bücher.de", "xn--bcher-kva.de"},
+		{"пример.рф", "xn--e1afmkfd.xn--p1ai"},
+		{"例え.テスト", "xn--r8jz45g.xn--zckzah"},
+		{"aéb-ñ.com", "xn--ab--bma6b.com"},
+		{"क्\u200dष.in", "xn--11b2ezcw70k.in"},
+		{"می\u200cخواهم.ir", "xn--mgbn2ecje63gr19l.ir"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ascii, func(t *testing.T) {
+			ascii, err := ToASCII(tt.unicode)
+			checkErr(t, err, "")
+			if ascii != tt.ascii {
+				t.Errorf("ToASCII() = %s, want %s", ascii, tt.ascii)
+			}
+
+			unicode, err := ToUnicode(tt.ascii)
+			checkErr(t, err, "")
+			if unicode != tt.unicode {
+				t.Errorf("ToUnicode() = %s, want %s", unicode, tt.unicode)
+			}
+		})
+	}
+
+	item := DomainItem{DomainName: "XN--BCHER-KVA.de"}
+	if name, _ := item.UnicodeName(); name != "bücher.de" {
+		t.Errorf("UnicodeName() = %s", name)
+	}
+	if name, _ := item.ASCIIName(); name != "xn--bcher-kva.de" {
+		t.Errorf("ASCIIName() = %s", name)
+	}
+}
+
+// TestInspectIDN tests the detection of mixed scripts and confusable characters.
+func TestInspectIDN(t *testing.T) {
+	tests := []struct {
+		name    string
+		domain  string
+		want    IDNReport
+		wantErr string
+	}{
+		{
+			name:   "ascii",
+			domain: "apple.com",
+			want:   IDNReport{"apple.com", "apple.com", []string{"Latin"}, false, false, "apple.com"},
+		},
+		{
+			name:   "whole-script confusable",
+			domain: "xn--80ak6aa92e.com",
+			want:   IDNReport{"xn--80ak6aa92e.com", "аррӏе.com", []string{"Cyrillic", "Latin"}, false, true, "apple.com"},
+		},
+		{
+			name:   "mixed script",
+			domain: "pаypal.com",
+			want:   IDNReport{"xn--pypal-4ve.com", "pаypal.com", []string{"Cyrillic", "Latin"}, true, true, "paypal.com"},
+		},
+		{
+			name:   "native cyrillic",
+			domain: "пример.рф",
+			want:   IDNReport{"xn--e1afmkfd.xn--p1ai", "пример.рф", []string{"Cyrillic"}, false, false, "пpиmep.pф"},
+		},
+		{
+			name:   "japanese with latin",
+			domain: "abc例え.jp",
+			want:   IDNReport{"xn--abc-b73b408n.jp", "abc例え.jp", []string{"Han", "Hiragana", "Latin"}, false, false, "abc例え.jp"},
+		},
+		{
+			name:    "zero width joiner",
+			domain:  "a\u200db.com",
+			wantErr: "invalid domain name \"a\u200db.com\": has the invisible joiner '\\u200d' outside of the context allowed by IDNA2008",
+		},
+		{
+			name:    "zero width joiner in punycode",
+			domain:  "xn--ab-m1t.com",
+			wantErr: `invalid domain name "xn--ab-m1t.com": has the invisible joiner '\u200d' outside of the context allowed by IDNA2008`,
+		},
+		{
+			name:    "zero width non-joiner",
+			domain:  "pay\u200cpal.com",
+			wantErr: "invalid domain name \"pay\u200cpal.com\": has the invisible joiner '\\u200c' outside of the context allowed by IDNA2008",
+		},
+		{
+			name:    "control character",
+			domain:  "\u0080.com",
+			wantErr: "invalid domain name \"\u0080.com\": has the character '\\u0080' disallowed by IDNA2008",
+		},
+		{
+			name:    "control character in punycode",
+			domain:  "xn--a.com",
+			wantErr: `invalid domain name "xn--a.com": has the character '\u0080' disallowed by IDNA2008`,
+		},
+		{
+			name:    "unassigned",
+			domain:  "a\u0378b.com",
+			wantErr: "invalid domain name \"a\u0378b.com\": has the character '\\u0378' disallowed by IDNA2008",
+		},
+		{
+			name:    "symbol",
+			domain:  "i❤.ws",
+			wantErr: `invalid domain name "i❤.ws": has the character '\u2764' disallowed by IDNA2008`,
+		},
+		{
+			name:    "malformed",
+			domain:  "",
+			wantErr: `invalid domain name "": is empty`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := InspectIDN(tt.domain)
+			checkErr(t, err, tt.wantErr)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InspectIDN() = %+v, want %+v", got, tt.want)
+			}
+			if got.Suspicious() != (tt.want.MixedScript || tt.want.Confusable) {
+				t.Errorf("Suspicious() = %v", got.Suspicious())
+			}
+		})
+	}
+}
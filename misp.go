@@ -0,0 +1,248 @@
+package registrantalert
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// MISPThreatLevel is the MISP threat level of the event.
+type MISPThreatLevel int
+
+// List of MISP threat levels.
+const (
+	MISPThreatLevelHigh      MISPThreatLevel = 1
+	MISPThreatLevelMedium    MISPThreatLevel = 2
+	MISPThreatLevelLow       MISPThreatLevel = 3
+	MISPThreatLevelUndefined MISPThreatLevel = 4
+)
+
+var _ = []MISPThreatLevel{
+	MISPThreatLevelHigh,
+	MISPThreatLevelMedium,
+	MISPThreatLevelLow,
+	MISPThreatLevelUndefined,
+}
+
+// MISPOptions configures the MISP event export.
+type MISPOptions struct {
+	// Search is the search the response originates from. It is recorded as the event comments.
+	Search Search
+
+	// Info is the event title. Default: "Registrant Alert: " followed by the search name or terms.
+	Info string
+
+	// Date is the time of the search run. If it's zero then the current time is used.
+	// The event date is the day of the run, the event UUID is derived from the full time.
+	Date time.Time
+
+	// RunID identifies the search run. If it's set then the event UUID is derived from it instead of Date.
+	RunID string
+
+	// ThreatLevel is the threat level of the event. Default: MISPThreatLevelUndefined.
+	ThreatLevel MISPThreatLevel
+
+	// Distribution is the MISP distribution level of the event, from 0 (your organisation only)
+	// to 4 (sharing group). Level 5 (inherit event) applies to the attributes only. Default: 0.
+	Distribution int
+
+	// SharingGroupID is the ID of the MISP sharing group. It is required by and only used with Distribution 4.
+	SharingGroupID int
+
+	// ToIDsActions are the actions whose domains are flagged for intrusion detection.
+	// Default: Added and Discovered.
+	ToIDsActions []Action
+}
+
+// MISPEvent is the MISP event document.
+type MISPEvent struct {
+	Event MISPEventDetails `json:"Event"`
+}
+
+// MISPEventDetails is the content of the MISP event.
+type MISPEventDetails struct {
+	UUID           string          `json:"uuid"`
+	Info           string          `json:"info"`
+	Date           string          `json:"date"`
+	ThreatLevelID  string          `json:"threat_level_id"`
+	Analysis       string          `json:"analysis"`
+	Distribution   string          `json:"distribution"`
+	SharingGroupID string          `json:"sharing_group_id,omitempty"`
+	Published      bool            `json:"published"`
+	Attributes     []MISPAttribute `json:"Attribute"`
+	Tags           []MISPTag       `json:"Tag,omitempty"`
+}
+
+// MISPAttribute is the attribute of the MISP event.
+type MISPAttribute struct {
+	UUID         string    `json:"uuid"`
+	Type         string    `json:"type"`
+	Category     string    `json:"category"`
+	Value        string    `json:"value"`
+	ToIDs        bool      `json:"to_ids"`
+	Distribution string    `json:"distribution"`
+	Comment      string    `json:"comment,omitempty"`
+	Tags         []MISPTag `json:"Tag,omitempty"`
+}
+
+// MISPTag is the tag of the MISP event or attribute.
+type MISPTag struct {
+	Name string `json:"name"`
+}
+
+// mispActionTag returns the machine tag of the action.
+func mispActionTag(action Action) MISPTag {
+	return MISPTag{Name: `registrant-alert:action="` + string(action) + `"`}
+}
+
+// NewMISPEvent converts the response to the MISP event.
+// Every domain becomes the domain attribute tagged with its action, the search terms are
+// recorded as the comment attributes. The UUIDs are derived from the search terms and the run ID
+// or the run time, so every run is a separate event and importing the same run again updates it.
+func NewMISPEvent(resp *RegistrantAlertResponse, opts MISPOptions) (*MISPEvent, error) {
+	if resp == nil {
+		return nil, &ArgError{"resp", "is required."}
+	}
+
+	threatLevel := opts.ThreatLevel
+	if threatLevel == 0 {
+		threatLevel = MISPThreatLevelUndefined
+	}
+	if threatLevel < MISPThreatLevelHigh || threatLevel > MISPThreatLevelUndefined {
+		return nil, &ArgError{"MISPOptions.ThreatLevel", "must be between 1 and 4."}
+	}
+
+	if opts.Distribution < 0 || opts.Distribution > 4 {
+		return nil, &ArgError{"MISPOptions.Distribution", "must be between 0 and 4."}
+	}
+
+	var sharingGroup string
+	switch {
+	case opts.Distribution == 4 && opts.SharingGroupID <= 0:
+		return nil, &ArgError{"MISPOptions.SharingGroupID", "is required by distribution 4."}
+	case opts.Distribution == 4:
+		sharingGroup = strconv.Itoa(opts.SharingGroupID)
+	case opts.SharingGroupID != 0:
+		return nil, &ArgError{"MISPOptions.SharingGroupID", "must be used with distribution 4 only."}
+	}
+
+	date := opts.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	eventDate := date.Format(dateFormat)
+
+	runID := opts.RunID
+	if runID == "" {
+		runID = date.UTC().Format(time.RFC3339Nano)
+	}
+
+	info := opts.Info
+	if info == "" {
+		info = "Registrant Alert: " + opts.Search.String()
+	}
+
+	toIDs := opts.ToIDsActions
+	if toIDs == nil {
+		toIDs = []Action{Added, Discovered}
+	}
+
+	eventUUID := newUUIDv5(libraryNamespace, "misp-event|"+opts.Search.key()+"|"+runID).String()
+	distribution := strconv.Itoa(opts.Distribution)
+
+	event := MISPEvent{Event: MISPEventDetails{
+		UUID:           eventUUID,
+		Info:           info,
+		Date:           eventDate,
+		ThreatLevelID:  strconv.Itoa(int(threatLevel)),
+		Analysis:       "2",
+		Distribution:   distribution,
+		SharingGroupID: sharingGroup,
+		Attributes:     []MISPAttribute{},
+		Tags:           []MISPTag{{Name: "registrant-alert"}},
+	}}
+
+	comments := []string{}
+	if opts.Search.Name != "" {
+		comments = append(comments, "Search: "+opts.Search.Name)
+	}
+	if terms := opts.Search.Terms(); terms != "" {
+		comments = append(comments, "Search terms: "+terms)
+	}
+	comments = append(comments, "Domains count: "+strconv.Itoa(resp.DomainsCount))
+
+	for _, comment := range comments {
+		event.Event.Attributes = append(event.Event.Attributes, MISPAttribute{
+			UUID:         newUUIDv5(libraryNamespace, "misp-comment|"+eventUUID+"|"+comment).String(),
+			Type:         "comment",
+			Category:     "Other",
+			Value:        comment,
+			Distribution: "5",
+		})
+	}
+
+	seen := make(map[string]bool)
+	for _, item := range resp.DomainsList {
+		if item.DomainName == "" {
+			continue
+		}
+
+		var itemDate string
+		if !item.Date.IsZero() {
+			itemDate = time.Time(item.Date).Format(dateFormat)
+		}
+
+		attributeUUID := newUUIDv5(libraryNamespace,
+			"misp-attribute|"+eventUUID+"|"+item.DomainName+"|"+string(item.Action)+"|"+itemDate).String()
+		if seen[attributeUUID] {
+			continue
+		}
+		seen[attributeUUID] = true
+
+		comment := string(item.Action)
+		if itemDate != "" {
+			comment += " on " + itemDate
+		}
+
+		attribute := MISPAttribute{
+			UUID:         attributeUUID,
+			Type:         "domain",
+			Category:     "Network activity",
+			Value:        item.DomainName,
+			Distribution: "5",
+			Comment:      comment,
+		}
+		if item.Action != "" {
+			attribute.Tags = []MISPTag{mispActionTag(item.Action)}
+		}
+		for _, action := range toIDs {
+			if item.Action == action {
+				attribute.ToIDs = true
+			}
+		}
+
+		event.Event.Attributes = append(event.Event.Attributes, attribute)
+	}
+
+	return &event, nil
+}
+
+// WriteMISP writes the response as the MISP event in JSON.
+func WriteMISP(w io.Writer, resp *RegistrantAlertResponse, opts MISPOptions) error {
+	event, err := NewMISPEvent(resp, opts)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(event); err != nil {
+		return fmt.Errorf("cannot write misp: %w", err)
+	}
+
+	return nil
+}
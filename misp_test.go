@@ -0,0 +1,105 @@
+package registrantalert
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestNewMISPEvent tests the MISP event export.
+func TestNewMISPEvent(t *testing.T) {
+	resp := &RegistrantAlertResponse{
+		DomainsList: []DomainItem{
+			{"batchwhois.com", Added, Time(time.Date(2022, 10, 30, 0, 0, 0, 0, time.UTC))},
+			{"whoisdodster.com", Dropped, Time(time.Date(2022, 10, 31, 0, 0, 0, 0, time.UTC))},
+		},
+		DomainsCount: 2,
+	}
+	opts := MISPOptions{
+		Search: Search{Name: "whois", AdvancedSearchTerms: []AdvancedSearchTerm{
+			{"RegistrantContact.Organization", "Whois Inc.", true},
+		}},
+		Date: time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	event, err := NewMISPEvent(resp, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := event.Event
+	if e.Info != "Registrant Alert: whois" || e.Date != "2022-11-01" || e.ThreatLevelID != "4" || e.Distribution != "0" {
+		t.Errorf("event = %+v", e)
+	}
+
+	wantComments := []string{
+		"Search: whois",
+		`Search terms: RegistrantContact.Organization = "Whois Inc."`,
+		"Domains count: 2",
+	}
+	if len(e.Attributes) != len(wantComments)+2 {
+		t.Fatalf("got %d attributes, want %d", len(e.Attributes), len(wantComments)+2)
+	}
+	for i, comment := range wantComments {
+		if e.Attributes[i].Type != "comment" || e.Attributes[i].Value != comment {
+			t.Errorf("attribute %d = %+v, want comment %q", i, e.Attributes[i], comment)
+		}
+	}
+
+	added, dropped := e.Attributes[3], e.Attributes[4]
+	if added.Type != "domain" || added.Value != "batchwhois.com" || !added.ToIDs ||
+		added.Tags[0].Name != `registrant-alert:action="added"` || added.Comment != "added on 2022-10-30" {
+		t.Errorf("added attribute = %+v", added)
+	}
+	if dropped.ToIDs || dropped.Tags[0].Name != `registrant-alert:action="dropped"` {
+		t.Errorf("dropped attribute = %+v", dropped)
+	}
+
+	var b bytes.Buffer
+	if err := WriteMISP(&b, resp, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded MISPEvent
+	if err := json.Unmarshal(b.Bytes(), &decoded); err != nil || decoded.Event.UUID != e.UUID {
+		t.Errorf("WriteMISP() wrote event %v, err %v", decoded.Event.UUID, err)
+	}
+
+	later := opts
+	later.Date = opts.Date.Add(time.Hour)
+	if event, err := NewMISPEvent(resp, later); err != nil || event.Event.UUID == e.UUID ||
+		event.Event.Attributes[3].UUID == added.UUID || event.Event.Date != e.Date {
+		t.Errorf("the run on the same day got the same UUIDs: %v, %v", event, err)
+	}
+
+	rerun := later
+	rerun.RunID = "run-1"
+	first, _ := NewMISPEvent(resp, rerun)
+	rerun.Date = later.Date.Add(time.Minute)
+	if second, _ := NewMISPEvent(resp, rerun); first.Event.UUID != second.Event.UUID {
+		t.Errorf("the run ID got UUIDs %v and %v", first.Event.UUID, second.Event.UUID)
+	}
+
+	_, err = NewMISPEvent(resp, MISPOptions{ThreatLevel: 5})
+	checkErr(t, err, `invalid argument: "MISPOptions.ThreatLevel" must be between 1 and 4.`)
+
+	for _, distribution := range []int{-1, 5} {
+		_, err = NewMISPEvent(resp, MISPOptions{Distribution: distribution})
+		checkErr(t, err, `invalid argument: "MISPOptions.Distribution" must be between 0 and 4.`)
+	}
+
+	shared, err := NewMISPEvent(resp, MISPOptions{Distribution: 4, SharingGroupID: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shared.Event.Distribution != "4" || shared.Event.SharingGroupID != "7" {
+		t.Errorf("distribution = %s, sharing group = %s", shared.Event.Distribution, shared.Event.SharingGroupID)
+	}
+
+	_, err = NewMISPEvent(resp, MISPOptions{Distribution: 4})
+	checkErr(t, err, `invalid argument: "MISPOptions.SharingGroupID" is required by distribution 4.`)
+
+	_, err = NewMISPEvent(resp, MISPOptions{Distribution: 1, SharingGroupID: 7})
+	checkErr(t, err, `invalid argument: "MISPOptions.SharingGroupID" must be used with distribution 4 only.`)
+}
@@ -1,8 +1,10 @@
 package registrantalert
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -22,30 +24,149 @@ var emptyTime Time
 
 const dateFormat = "2006-01-02"
 
+// timeLayouts are the layouts accepted by ParseTime in addition to dates.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// ParseTime parses the date in the YYYY-MM-DD format or the RFC 3339 timestamp.
+// Dates and timestamps without the time zone are parsed in the location, which defaults to UTC.
+// An empty string is parsed to the zero Time.
+func ParseTime(value string, loc *time.Location) (Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return emptyTime, nil
+	}
+
+	if v, err := time.ParseInLocation(dateFormat, value, loc); err == nil {
+		return Time(v), nil
+	}
+	for _, layout := range timeLayouts {
+		if v, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return Time(v), nil
+		}
+	}
+
+	return emptyTime, fmt.Errorf("cannot parse time %q: must be a date in YYYY-MM-DD format or an RFC 3339 timestamp", value)
+}
+
 // UnmarshalJSON decodes time as Registrant Alert API does.
+// RFC 3339 timestamps are accepted as well, see ParseTime.
 func (t *Time) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+
 	str, err := unmarshalString(b)
 	if err != nil {
 		return err
 	}
-	if str == "" {
-		*t = emptyTime
-		return nil
-	}
-	v, err := time.Parse(dateFormat, str)
+
+	v, err := ParseTime(str, time.UTC)
 	if err != nil {
 		return err
 	}
-	*t = Time(v)
+	*t = v
 	return nil
 }
 
 // MarshalJSON encodes time as Registrant Alert API does.
 func (t Time) MarshalJSON() ([]byte, error) {
-	if t == emptyTime {
-		return []byte(`""`), nil
+	return []byte(`"` + t.String() + `"`), nil
+}
+
+// UnmarshalText decodes time as UnmarshalJSON does.
+func (t *Time) UnmarshalText(b []byte) error {
+	v, err := ParseTime(string(b), time.UTC)
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// MarshalText encodes time as MarshalJSON does.
+func (t Time) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// Time returns the time as time.Time.
+func (t Time) Time() time.Time {
+	return time.Time(t)
+}
+
+// In returns the time in the location.
+func (t Time) In(loc *time.Location) time.Time {
+	return time.Time(t).In(loc)
+}
+
+// String returns the date in the YYYY-MM-DD format if the time is midnight in UTC,
+// or the RFC 3339 timestamp otherwise, so that the offset is kept. The zero Time is an empty string.
+func (t Time) String() string {
+	if t.IsZero() {
+		return ""
+	}
+
+	v := time.Time(t)
+	_, offset := v.Zone()
+	if offset == 0 && v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 && v.Nanosecond() == 0 {
+		return v.Format(dateFormat)
+	}
+	return v.Format(time.RFC3339Nano)
+}
+
+// IsZero reports whether the time is empty.
+func (t Time) IsZero() bool {
+	return time.Time(t).IsZero()
+}
+
+// Before reports whether the time is before u.
+func (t Time) Before(u Time) bool {
+	return time.Time(t).Before(time.Time(u))
+}
+
+// After reports whether the time is after u.
+func (t Time) After(u Time) bool {
+	return time.Time(t).After(time.Time(u))
+}
+
+// Equal reports whether the time and u represent the same instant, whatever their locations.
+func (t Time) Equal(u Time) bool {
+	return time.Time(t).Equal(time.Time(u))
+}
+
+// Scan implements sql.Scanner. It accepts time.Time, strings and byte slices parsed as by ParseTime,
+// and NULL as the zero Time.
+func (t *Time) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*t = emptyTime
+		return nil
+	case time.Time:
+		*t = Time(v)
+		return nil
+	case string:
+		return t.UnmarshalText([]byte(v))
+	case []byte:
+		return t.UnmarshalText(v)
+	}
+
+	return fmt.Errorf("cannot scan %T into Time", src)
+}
+
+// Value implements driver.Valuer. The zero Time is stored as NULL.
+func (t Time) Value() (driver.Value, error) {
+	if t.IsZero() {
+		return nil, nil
 	}
-	return []byte(`"` + time.Time(t).Format(dateFormat) + `"`), nil
+	return time.Time(t), nil
 }
 
 // BasicSearchTerms is a part of the Registrant Alert API request.
@@ -72,49 +193,80 @@ type AdvancedSearchTerm struct {
 	ExactMatch bool `json:"exactMatch,omitempty"`
 }
 
-// registrantAlertRequest is the request struct for Registrant Alert API.
+// registrantAlertRequest is the set of the optional parameters the options are applied to.
+// The requests are sent in the form of Request, see encodeRequest.
 type registrantAlertRequest struct {
-	// APIKey is the user's API key.
-	APIKey string `json:"apiKey"`
-
-	// BasicSearchTerms is the set of search terms for the Basic search.
-	BasicSearchTerms *BasicSearchTerms `json:"basicSearchTerms,omitempty"`
-
-	// BasicSearchTerms is the set of search terms for the Advanced search.
-	AdvancedSearchTerms []AdvancedSearchTerm `json:"advancedSearchTerms,omitempty"`
-
-	// SinceDate If present, search through activities discovered since the given date.
-	SinceDate string `json:"sinceDate,omitempty"`
-
-	// Mode is the mode of the API call. Acceptable values: preview | purchase.
-	Mode string `json:"mode,omitempty"`
+	// ResponseFormat is the response output format JSON | XML.
+	ResponseFormat ResponseFormat
 
 	// Punycode If true, domain names in the response will be encoded to punycode.
-	Punycode bool `json:"punycode,omitempty"`
+	Punycode bool
 
-	// ResponseFormat is the response output format JSON | XML.
-	ResponseFormat string `json:"responseFormat,omitempty"`
+	// SinceDate If present, search through activities discovered since the given date.
+	SinceDate string
 
 	// CreatedDateFrom If present, search through domains created after the given date.
-	CreatedDateFrom string `json:"createdDateFrom,omitempty"`
+	CreatedDateFrom string
 
 	// CreatedDateTo If present, search through domains created before the given date.
-	CreatedDateTo string `json:"createdDateTo,omitempty"`
+	CreatedDateTo string
 
 	// UpdatedDateFrom If present, search through domains updated after the given date.
-	UpdatedDateFrom string `json:"updatedDateFrom,omitempty"`
+	UpdatedDateFrom string
 
 	// UpdatedDateTo If present, search through domains updated before the given date.
-	UpdatedDateTo string `json:"updatedDateTo,omitempty"`
+	UpdatedDateTo string
 
 	// ExpiredDateFrom If present, search through domains expired after the given date.
-	ExpiredDateFrom string `json:"expiredDateFrom,omitempty"`
+	ExpiredDateFrom string
+
+	// ExpiredDateTo If present, search through domains expired before the given date.
+	ExpiredDateTo string
+}
+
+// ResponseFormat is the output format of the API response.
+type ResponseFormat string
+
+// List of supported response formats.
+const (
+	JSON ResponseFormat = "json"
+	XML  ResponseFormat = "xml"
+)
+
+var _ = []ResponseFormat{
+	JSON,
+	XML,
+}
 
-	// ExpiredDateFrom If present, search through domains expired before the given date.
-	ExpiredDateTo string `json:"expiredDateTo,omitempty"`
+// ParseResponseFormat converts the case-insensitive format name to ResponseFormat.
+func ParseResponseFormat(format string) (ResponseFormat, error) {
+	f := ResponseFormat(strings.ToLower(strings.TrimSpace(format)))
+	if !f.IsValid() {
+		return "", &ArgError{"responseFormat", "must be json or xml."}
+	}
+	return f, nil
+}
+
+// IsValid reports whether the format is supported by the API.
+func (f ResponseFormat) IsValid() bool {
+	return f == JSON || f == XML
+}
+
+// String returns the format name.
+func (f ResponseFormat) String() string {
+	return string(f)
+}
+
+// mediaType returns the MIME type of the format.
+func (f ResponseFormat) mediaType() string {
+	if f == XML {
+		return "application/xml"
+	}
+	return mediaType
 }
 
 // Action is a wrapper on string.
+// Values not known to this library are preserved as received, see Action.Kind.
 type Action string
 
 // List of possible actions.
@@ -123,15 +275,85 @@ const (
 	Updated    Action = "updated"
 	Dropped    Action = "dropped"
 	Discovered Action = "discovered"
+
+	// ActionUnknown is the kind of the actions not known to this library.
+	ActionUnknown Action = "unknown"
 )
 
-var _ = []Action{
+// knownActions are the actions returned by Registrant Alert API.
+var knownActions = []Action{
 	Added,
 	Updated,
 	Dropped,
 	Discovered,
 }
 
+// normalizeAction returns the known action matching the case-insensitive value,
+// or the value unchanged if it's unknown.
+func normalizeAction(value string) Action {
+	normalized := Action(strings.ToLower(strings.TrimSpace(value)))
+	if normalized.IsValid() {
+		return normalized
+	}
+	return Action(value)
+}
+
+// ParseAction converts the case-insensitive action name to Action.
+// Unlike the decoding functions it rejects unknown actions.
+func ParseAction(action string) (Action, error) {
+	a := normalizeAction(action)
+	if !a.IsValid() {
+		return "", &ArgError{"action", "must be one of added, updated, dropped, discovered."}
+	}
+	return a, nil
+}
+
+// IsValid reports whether the action is one of the known actions.
+func (a Action) IsValid() bool {
+	for _, known := range knownActions {
+		if a == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Kind returns the action if it's known, ActionUnknown otherwise.
+// The raw value of the unknown action remains available as the Action itself.
+func (a Action) Kind() Action {
+	if a.IsValid() {
+		return a
+	}
+	return ActionUnknown
+}
+
+// String returns the action name.
+func (a Action) String() string {
+	return string(a)
+}
+
+// UnmarshalText decodes the action. The known actions are matched case-insensitively,
+// unknown values are kept as is.
+func (a *Action) UnmarshalText(b []byte) error {
+	*a = normalizeAction(string(b))
+	return nil
+}
+
+// UnmarshalJSON decodes the action as UnmarshalText does. The value must be a JSON string.
+func (a *Action) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+
+	str, err := unmarshalString(b)
+	if err != nil {
+		return fmt.Errorf("cannot decode action: %w", err)
+	}
+
+	*a = normalizeAction(str)
+	return nil
+}
+
 // DomainItem is a part of the Registrant Alert API response.
 type DomainItem struct {
 	// DomainName is the full domain name.
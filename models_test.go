@@ -3,6 +3,7 @@ package registrantalert
 import (
 	"encoding/json"
 	"testing"
+	"time"
 )
 
 // TestTime tests the Time conversion functions.
@@ -19,7 +20,22 @@ func TestTime(t *testing.T) {
 		},
 		{
 			name:   `"2006-01-02T15:04:05Z08:00"`,
-			decErr: `parsing time "2006-01-02T15:04:05Z08:00": extra text: "T15:04:05Z08:00"`,
+			decErr: `cannot parse time "2006-01-02T15:04:05Z08:00": must be a date in YYYY-MM-DD format or an RFC 3339 timestamp`,
+			encErr: "",
+		},
+		{
+			name:   `"2006-01-02T15:04:05+08:00"`,
+			decErr: "",
+			encErr: "",
+		},
+		{
+			name:   `"2006-01-02T00:00:00+02:00"`,
+			decErr: "",
+			encErr: "",
+		},
+		{
+			name:   `"2006-01-02T15:04:05.123Z"`,
+			decErr: "",
 			encErr: "",
 		},
 		{
@@ -47,6 +63,11 @@ func TestTime(t *testing.T) {
 			if string(bb) != tt.name {
 				t.Errorf("got = %v, want %v", string(bb), tt.name)
 			}
+
+			var decoded Time
+			if err := json.Unmarshal(bb, &decoded); err != nil || !decoded.Equal(v) {
+				t.Errorf("round trip got = %v, want %v", decoded, v)
+			}
 		})
 	}
 }
@@ -113,6 +134,179 @@ func TestMessages(t *testing.T) {
 	}
 }
 
+// TestParseResponseFormat tests the ParseResponseFormat function.
+func TestParseResponseFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    ResponseFormat
+		wantErr string
+	}{
+		{"json", JSON, ""},
+		{"XML", XML, ""},
+		{" Json ", JSON, ""},
+		{"csv", "", `invalid argument: "responseFormat" must be json or xml.`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseResponseFormat(tt.name)
+			checkErr(t, err, tt.wantErr)
+			if got != tt.want {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseAction tests the ParseAction function.
+func TestParseAction(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Action
+		wantErr string
+	}{
+		{"added", Added, ""},
+		{" Dropped", Dropped, ""},
+		{"DISCOVERED", Discovered, ""},
+		{"renewed", "", `invalid argument: "action" must be one of added, updated, dropped, discovered.`},
+		{"unknown", "", `invalid argument: "action" must be one of added, updated, dropped, discovered.`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAction(tt.name)
+			checkErr(t, err, tt.wantErr)
+			if got != tt.want {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestAction tests the Action decoding functions.
+func TestAction(t *testing.T) {
+	tests := []struct {
+		name     string
+		want     Action
+		wantKind Action
+		decErr   string
+	}{
+		{`"added"`, Added, Added, ""},
+		{`"Updated"`, Updated, Updated, ""},
+		{`"Renewed"`, "Renewed", ActionUnknown, ""},
+		{`""`, "", ActionUnknown, ""},
+		{`1`, "", "", "cannot decode action: json: cannot unmarshal number into Go value of type string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var item DomainItem
+
+			err := json.Unmarshal([]byte(`{"action":`+tt.name+`}`), &item)
+			checkErr(t, err, tt.decErr)
+			if tt.decErr != "" {
+				return
+			}
+
+			if item.Action != tt.want || item.Action.Kind() != tt.wantKind {
+				t.Errorf("got = %v (%v), want %v (%v)", item.Action, item.Action.Kind(), tt.want, tt.wantKind)
+			}
+			if item.Action.IsValid() != (tt.wantKind != ActionUnknown) {
+				t.Errorf("IsValid() = %v", item.Action.IsValid())
+			}
+
+			var text Action
+			_ = text.UnmarshalText([]byte(tt.name[1 : len(tt.name)-1]))
+			if text != tt.want {
+				t.Errorf("UnmarshalText() = %v, want %v", text, tt.want)
+			}
+		})
+	}
+}
+
+// TestTimeMethods tests the Time methods and the tolerant parsing.
+func TestTimeMethods(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+
+	tests := []struct {
+		value   string
+		loc     *time.Location
+		want    time.Time
+		str     string
+		wantErr string
+	}{
+		{"2022-10-30", nil, time.Date(2022, 10, 30, 0, 0, 0, 0, time.UTC), "2022-10-30", ""},
+		{"2022-10-30", loc, time.Date(2022, 10, 30, 0, 0, 0, 0, loc), "2022-10-30T00:00:00+03:00", ""},
+		{"2022-10-30T10:20:30Z", loc, time.Date(2022, 10, 30, 10, 20, 30, 0, time.UTC), "2022-10-30T10:20:30Z", ""},
+		{"2022-10-30 10:20:30", loc, time.Date(2022, 10, 30, 10, 20, 30, 0, loc), "2022-10-30T10:20:30+03:00", ""},
+		{"", nil, time.Time{}, "", ""},
+		{"30.10.2022", nil, time.Time{}, "",
+			`cannot parse time "30.10.2022": must be a date in YYYY-MM-DD format or an RFC 3339 timestamp`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseTime(tt.value, tt.loc)
+			checkErr(t, err, tt.wantErr)
+			if !got.Time().Equal(tt.want) || got.String() != tt.str {
+				t.Errorf("got = %v (%v), want %v (%v)", got.Time(), got, tt.want, tt.str)
+			}
+			if got.IsZero() != tt.want.IsZero() {
+				t.Errorf("IsZero() = %v", got.IsZero())
+			}
+
+			text, _ := got.MarshalText()
+			var decoded Time
+			if err := decoded.UnmarshalText(text); err != nil || decoded.String() != tt.str {
+				t.Errorf("UnmarshalText(%s) = %v, %v", text, decoded, err)
+			}
+		})
+	}
+
+	day1 := Time(time.Date(2022, 10, 29, 0, 0, 0, 0, time.UTC))
+	day2 := Time(time.Date(2022, 10, 30, 0, 0, 0, 0, time.UTC))
+	if !day1.Before(day2) || day1.After(day2) || !day2.After(day1) {
+		t.Error("Before/After are inconsistent")
+	}
+	if !day2.Equal(Time(day2.In(loc))) || day1.Equal(day2) {
+		t.Error("Equal is inconsistent")
+	}
+	if got := day2.In(loc).Hour(); got != 3 {
+		t.Errorf("In().Hour() = %d", got)
+	}
+}
+
+// TestTimeSQL tests the sql.Scanner and driver.Valuer implementations.
+func TestTimeSQL(t *testing.T) {
+	day := time.Date(2022, 10, 30, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    Time
+		wantErr string
+	}{
+		{"nil", nil, emptyTime, ""},
+		{"time", day, Time(day), ""},
+		{"string", "2022-10-30", Time(day), ""},
+		{"bytes", []byte("2022-10-30T00:00:00Z"), Time(day), ""},
+		{"int", 1, emptyTime, "cannot scan int into Time"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Time
+			err := got.Scan(tt.src)
+			checkErr(t, err, tt.wantErr)
+			if !got.Time().Equal(tt.want.Time()) {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if v, err := emptyTime.Value(); v != nil || err != nil {
+		t.Errorf("Value() = %v, %v", v, err)
+	}
+	if v, err := Time(day).Value(); v != day || err != nil {
+		t.Errorf("Value() = %v, %v", v, err)
+	}
+}
+
 func checkErr(t *testing.T, err error, want string) {
 	if (err != nil || want != "") && (err == nil || err.Error() != want) {
 		t.Errorf("error = %v, wantErr %v", err, want)
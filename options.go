@@ -1,6 +1,9 @@
 package registrantalert
 
 import (
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -8,7 +11,7 @@ import (
 type Option func(v *registrantAlertRequest)
 
 var _ = []Option{
-	OptionResponseFormat("JSON"),
+	OptionResponseFormat(JSON),
 	OptionSinceDate(time.Time{}),
 	OptionPunycode(true),
 	OptionCreatedDateFrom(time.Time{}),
@@ -17,12 +20,21 @@ var _ = []Option{
 	OptionUpdatedDateTo(time.Time{}),
 	OptionExpiredDateFrom(time.Time{}),
 	OptionExpiredDateTo(time.Time{}),
+	OptionSinceLast(0),
+	OptionSinceStartOf(Today),
+	OptionCreatedWithin(0, 0),
+	OptionUpdatedWithin(0, 0),
+	OptionExpiredWithin(0, 0),
+	OptionCreatedDuring(Today),
+	OptionUpdatedDuring(Today),
+	OptionExpiredDuring(Today),
 }
 
 // OptionResponseFormat sets Response output format json | xml. Default: json.
-func OptionResponseFormat(outputFormat string) Option {
+// The format name is case-insensitive, unsupported formats are rejected before sending the request.
+func OptionResponseFormat(outputFormat ResponseFormat) Option {
 	return func(v *registrantAlertRequest) {
-		v.ResponseFormat = outputFormat
+		v.ResponseFormat = ResponseFormat(strings.ToLower(strings.TrimSpace(string(outputFormat))))
 	}
 }
 
@@ -82,3 +94,158 @@ func OptionExpiredDateTo(date time.Time) Option {
 		v.ExpiredDateTo = date.Format(dateFormat)
 	}
 }
+
+// RequestParams is the serializable form of the request options.
+// Dates are in the YYYY-MM-DD format, empty fields are not set.
+type RequestParams struct {
+	// ResponseFormat is the response output format json | xml.
+	ResponseFormat ResponseFormat `json:"responseFormat,omitempty"`
+
+	// Punycode defines whether domain names in the response will be encoded to Punycode.
+	Punycode *bool `json:"punycode,omitempty"`
+
+	// SinceDate is the date since which activities are searched through.
+	SinceDate string `json:"sinceDate,omitempty"`
+
+	// CreatedDateFrom is the date after which domains were created.
+	CreatedDateFrom string `json:"createdDateFrom,omitempty"`
+
+	// CreatedDateTo is the date before which domains were created.
+	CreatedDateTo string `json:"createdDateTo,omitempty"`
+
+	// UpdatedDateFrom is the date after which domains were updated.
+	UpdatedDateFrom string `json:"updatedDateFrom,omitempty"`
+
+	// UpdatedDateTo is the date before which domains were updated.
+	UpdatedDateTo string `json:"updatedDateTo,omitempty"`
+
+	// ExpiredDateFrom is the date after which domains expired.
+	ExpiredDateFrom string `json:"expiredDateFrom,omitempty"`
+
+	// ExpiredDateTo is the date before which domains expired.
+	ExpiredDateTo string `json:"expiredDateTo,omitempty"`
+}
+
+// Options converts the parameters to the list of Options.
+func (p RequestParams) Options() ([]Option, error) {
+	var opts []Option
+
+	if p.ResponseFormat != "" {
+		format, err := ParseResponseFormat(string(p.ResponseFormat))
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, OptionResponseFormat(format))
+	}
+
+	if p.Punycode != nil {
+		opts = append(opts, OptionPunycode(*p.Punycode))
+	}
+
+	dates := []struct {
+		name   string
+		value  string
+		option func(time.Time) Option
+	}{
+		{"sinceDate", p.SinceDate, OptionSinceDate},
+		{"createdDateFrom", p.CreatedDateFrom, OptionCreatedDateFrom},
+		{"createdDateTo", p.CreatedDateTo, OptionCreatedDateTo},
+		{"updatedDateFrom", p.UpdatedDateFrom, OptionUpdatedDateFrom},
+		{"updatedDateTo", p.UpdatedDateTo, OptionUpdatedDateTo},
+		{"expiredDateFrom", p.ExpiredDateFrom, OptionExpiredDateFrom},
+		{"expiredDateTo", p.ExpiredDateTo, OptionExpiredDateTo},
+	}
+
+	for _, date := range dates {
+		if date.value == "" {
+			continue
+		}
+
+		t, err := time.Parse(dateFormat, date.value)
+		if err != nil {
+			return nil, &ArgError{date.name, "must be a date in YYYY-MM-DD format."}
+		}
+
+		opts = append(opts, date.option(t))
+	}
+
+	return opts, nil
+}
+
+// ParamsFromOptions applies the options to the empty RequestParams and returns what they set.
+// Relative options, e.g. OptionSinceLast, are resolved to the dates at the time of the call,
+// so their parameters and the String form used as a cache key change from day to day.
+// Use the relative fields of SavedSearch to store relative dates.
+func ParamsFromOptions(opts ...Option) (RequestParams, error) {
+	if err := validateOptions(opts...); err != nil {
+		return RequestParams{}, err
+	}
+
+	// Punycode has no unset state in the request, so the options are applied
+	// to both values to find out whether any of them sets it.
+	request := &registrantAlertRequest{Punycode: true}
+	probe := &registrantAlertRequest{Punycode: false}
+	for _, opt := range opts {
+		opt(request)
+		opt(probe)
+	}
+
+	p := RequestParams{
+		ResponseFormat:  request.ResponseFormat,
+		SinceDate:       request.SinceDate,
+		CreatedDateFrom: request.CreatedDateFrom,
+		CreatedDateTo:   request.CreatedDateTo,
+		UpdatedDateFrom: request.UpdatedDateFrom,
+		UpdatedDateTo:   request.UpdatedDateTo,
+		ExpiredDateFrom: request.ExpiredDateFrom,
+		ExpiredDateTo:   request.ExpiredDateTo,
+	}
+
+	if request.Punycode == probe.Punycode {
+		punycode := request.Punycode
+		p.Punycode = &punycode
+	}
+
+	return p, nil
+}
+
+// values returns the parameters that are set as the URL query values.
+func (p RequestParams) values() url.Values {
+	values := url.Values{}
+
+	set := func(name, value string) {
+		if value != "" {
+			values.Set(name, value)
+		}
+	}
+
+	set("responseFormat", string(p.ResponseFormat))
+	if p.Punycode != nil {
+		set("punycode", strconv.FormatBool(*p.Punycode))
+	}
+	set("sinceDate", p.SinceDate)
+	set("createdDateFrom", p.CreatedDateFrom)
+	set("createdDateTo", p.CreatedDateTo)
+	set("updatedDateFrom", p.UpdatedDateFrom)
+	set("updatedDateTo", p.UpdatedDateTo)
+	set("expiredDateFrom", p.ExpiredDateFrom)
+	set("expiredDateTo", p.ExpiredDateTo)
+
+	return values
+}
+
+// String returns the parameters that are set in the canonical form sorted by the name,
+// e.g. "punycode=false&sinceDate=2022-10-01". It's suitable for logs and cache keys.
+func (p RequestParams) String() string {
+	return p.values().Encode()
+}
+
+// IsZero reports whether no parameters are set.
+func (p RequestParams) IsZero() bool {
+	return len(p.values()) == 0
+}
+
+// Equal reports whether the parameters set the same values.
+func (p RequestParams) Equal(other RequestParams) bool {
+	return p.String() == other.String()
+}
@@ -21,6 +21,12 @@ func TestOptions(t *testing.T) {
 			option: OptionResponseFormat("json"),
 			want:   "json",
 		},
+		{
+			name:   "responseFormat",
+			values: &registrantAlertRequest{},
+			option: OptionResponseFormat(" XML"),
+			want:   "xml",
+		},
 		{
 			name:   "sinceDate",
 			values: &registrantAlertRequest{},
@@ -77,7 +83,7 @@ func TestOptions(t *testing.T) {
 
 			switch tt.name {
 			case "responseFormat":
-				got = tt.values.ResponseFormat
+				got = string(tt.values.ResponseFormat)
 			case "sinceDate":
 				got = tt.values.SinceDate
 			case "createdDateFrom":
@@ -101,3 +107,54 @@ func TestOptions(t *testing.T) {
 		})
 	}
 }
+
+// TestParamsFromOptions tests the introspection of the options and the round trip through RequestParams.
+func TestParamsFromOptions(t *testing.T) {
+	t.Parallel()
+
+	cal := fixedCalendar(time.Date(2022, 11, 1, 12, 0, 0, 0, time.UTC))
+
+	yes, no := true, false
+	tests := []struct {
+		name       string
+		opts       []Option
+		want       RequestParams
+		wantString string
+	}{
+		{"no options", nil, RequestParams{}, ""},
+		{"punycode true", []Option{OptionPunycode(true)}, RequestParams{Punycode: &yes}, "punycode=true"},
+		{"punycode false", []Option{OptionPunycode(false)}, RequestParams{Punycode: &no}, "punycode=false"},
+		{"later option wins", []Option{OptionResponseFormat("XML"), cal.OptionSinceLast(7 * 24 * time.Hour), OptionResponseFormat(JSON)},
+			RequestParams{ResponseFormat: JSON, SinceDate: "2022-10-25"}, "responseFormat=json&sinceDate=2022-10-25"},
+		{"dates", []Option{cal.OptionCreatedDuring(LastFullMonth), OptionExpiredDateTo(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))},
+			RequestParams{CreatedDateFrom: "2022-10-01", CreatedDateTo: "2022-10-31", ExpiredDateTo: "2023-01-01"},
+			"createdDateFrom=2022-10-01&createdDateTo=2022-10-31&expiredDateTo=2023-01-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParamsFromOptions(tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParamsFromOptions() = %v, want %v", got, tt.want)
+			}
+			if got.String() != tt.wantString || got.IsZero() != (tt.wantString == "") {
+				t.Errorf("String() = %q, want %q", got.String(), tt.wantString)
+			}
+
+			opts, err := got.Options()
+			if err != nil {
+				t.Fatal(err)
+			}
+			again, err := ParamsFromOptions(opts...)
+			if err != nil || !reflect.DeepEqual(again, got) {
+				t.Errorf("round trip = %v, %v, want %v", again, err, got)
+			}
+		})
+	}
+
+	_, err := ParamsFromOptions(OptionPunycode(true), nil)
+	checkErr(t, err, `invalid argument: "Option" can not be nil`)
+}
@@ -0,0 +1,457 @@
+package registrantalert
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Punycode parameters as defined by RFC 3492.
+const (
+	punycodeBase        = 36
+	punycodeTMin        = 1
+	punycodeTMax        = 26
+	punycodeSkew        = 38
+	punycodeDamp        = 700
+	punycodeInitialBias = 72
+	punycodeInitialN    = 128
+)
+
+// acePrefix is the prefix of the Punycode-encoded labels.
+const acePrefix = "xn--"
+
+// Punycode errors.
+var (
+	errPunycodeOverflow = errors.New("punycode: overflow")
+	errPunycodeInvalid  = errors.New("punycode: invalid encoding")
+)
+
+// punycodeAdapt is the bias adaptation function of RFC 3492.
+func punycodeAdapt(delta, numPoints int, firstTime bool) int {
+	if firstTime {
+		delta /= punycodeDamp
+	} else {
+		delta /= 2
+	}
+	delta += delta / numPoints
+
+	k := 0
+	for delta > ((punycodeBase-punycodeTMin)*punycodeTMax)/2 {
+		delta /= punycodeBase - punycodeTMin
+		k += punycodeBase
+	}
+
+	return k + (punycodeBase-punycodeTMin+1)*delta/(delta+punycodeSkew)
+}
+
+// punycodeDigit returns the character of the digit.
+func punycodeDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+	return byte('0' + d - 26)
+}
+
+// punycodeThreshold returns the threshold for the position k.
+func punycodeThreshold(k, bias int) int {
+	switch {
+	case k <= bias:
+		return punycodeTMin
+	case k >= bias+punycodeTMax:
+		return punycodeTMax
+	}
+	return k - bias
+}
+
+// punycodeEncode encodes the label to Punycode without the ACE prefix.
+func punycodeEncode(label string) (string, error) {
+	runes := []rune(label)
+
+	var out strings.Builder
+	for _, r := range runes {
+		if r < utf8.RuneSelf {
+			out.WriteByte(byte(r))
+		}
+	}
+
+	basic := out.Len()
+	handled := basic
+	if basic > 0 {
+		out.WriteByte('-')
+	}
+
+	n, delta, bias := punycodeInitialN, 0, punycodeInitialBias
+
+	for handled < len(runes) {
+		m := int(utf8.MaxRune) + 1
+		for _, r := range runes {
+			if int(r) >= n && int(r) < m {
+				m = int(r)
+			}
+		}
+
+		if (m-n)*(handled+1) > 1<<30 {
+			return "", errPunycodeOverflow
+		}
+		delta += (m - n) * (handled + 1)
+		n = m
+
+		for _, r := range runes {
+			if int(r) < n {
+				delta++
+			}
+			if int(r) != n {
+				continue
+			}
+
+			q := delta
+			for k := punycodeBase; ; k += punycodeBase {
+				t := punycodeThreshold(k, bias)
+				if q < t {
+					break
+				}
+				out.WriteByte(punycodeDigit(t + (q-t)%(punycodeBase-t)))
+				q = (q - t) / (punycodeBase - t)
+			}
+			out.WriteByte(punycodeDigit(q))
+
+			bias = punycodeAdapt(delta, handled+1, handled == basic)
+			delta = 0
+			handled++
+		}
+
+		delta++
+		n++
+	}
+
+	return out.String(), nil
+}
+
+// punycodeDigitValue returns the value of the digit character.
+func punycodeDigitValue(c byte) (int, bool) {
+	switch {
+	case c >= 'a' && c <= 'z':
+		return int(c - 'a'), true
+	case c >= 'A' && c <= 'Z':
+		return int(c - 'A'), true
+	case c >= '0' && c <= '9':
+		return int(c-'0') + 26, true
+	}
+	return 0, false
+}
+
+// punycodeDecode decodes the Punycode label without the ACE prefix.
+func punycodeDecode(encoded string) (string, error) {
+	var output []rune
+
+	pos := 0
+	if i := strings.LastIndexByte(encoded, '-'); i >= 0 {
+		for j := 0; j < i; j++ {
+			if encoded[j] >= utf8.RuneSelf {
+				return "", errPunycodeInvalid
+			}
+			output = append(output, rune(encoded[j]))
+		}
+		pos = i + 1
+	}
+
+	n, i, bias := punycodeInitialN, 0, punycodeInitialBias
+
+	for pos < len(encoded) {
+		oldI, w := i, 1
+		for k := punycodeBase; ; k += punycodeBase {
+			if pos >= len(encoded) {
+				return "", errPunycodeInvalid
+			}
+			digit, ok := punycodeDigitValue(encoded[pos])
+			pos++
+			if !ok {
+				return "", errPunycodeInvalid
+			}
+			if digit > (1<<30-i)/w {
+				return "", errPunycodeOverflow
+			}
+			i += digit * w
+
+			t := punycodeThreshold(k, bias)
+			if digit < t {
+				break
+			}
+			if w > (1<<30)/(punycodeBase-t) {
+				return "", errPunycodeOverflow
+			}
+			w *= punycodeBase - t
+		}
+
+		bias = punycodeAdapt(i-oldI, len(output)+1, oldI == 0)
+		if i/(len(output)+1) > 1<<30-n {
+			return "", errPunycodeOverflow
+		}
+		n += i / (len(output) + 1)
+		i %= len(output) + 1
+
+		if n > utf8.MaxRune || !utf8.ValidRune(rune(n)) {
+			return "", errPunycodeInvalid
+		}
+
+		output = append(output, 0)
+		copy(output[i+1:], output[i:])
+		output[i] = rune(n)
+		i++
+	}
+
+	return string(output), nil
+}
+
+// isASCII reports whether the string contains ASCII characters only.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// ligatures are the Latin ligatures mapped to their letters by UTS #46.
+var ligatures = map[rune]string{
+	'ﬀ': "ff",
+	'ﬁ': "fi",
+	'ﬂ': "fl",
+	'ﬃ': "ffi",
+	'ﬄ': "ffl",
+	'ﬅ': "st",
+	'ﬆ': "st",
+}
+
+// mapName applies the part of the UTS #46 mapping that differs in practice: fullwidth ASCII
+// is mapped to ASCII and Latin ligatures to their letters. The library has no Unicode
+// normalization tables, so names that are not in NFC, i.e. with combining diacritical marks
+// after Latin, Greek or Cyrillic letters, and names with halfwidth forms are rejected
+// instead of being mapped. It returns the error message if the name is rejected.
+func mapName(name string) (string, string) {
+	if isASCII(name) {
+		return name, ""
+	}
+
+	var b strings.Builder
+	var prev rune
+	for _, r := range name {
+		switch {
+		case r >= 0xFF01 && r <= 0xFF5E:
+			r -= 0xFF01 - '!'
+		case ligatures[r] != "":
+			b.WriteString(ligatures[r])
+			prev = r
+			continue
+		case r >= 0xFF62 && r <= 0xFFEE:
+			return "", "has a halfwidth character " + strconv.QuoteRune(r) + " that must be mapped to the fullwidth form"
+		case r >= 0x0300 && r <= 0x036F && unicode.In(prev, unicode.Latin, unicode.Greek, unicode.Cyrillic):
+			return "", "is not in Unicode NFC form, the combining mark " + strconv.QuoteRuneToASCII(r) + " must be composed"
+		}
+		b.WriteRune(r)
+		prev = r
+	}
+
+	return b.String(), ""
+}
+
+// Zero-width joiners allowed by IDNA2008 only in the context defined by RFC 5892, Appendix A.1 and A.2.
+const (
+	zeroWidthNonJoiner = '\u200C'
+	zeroWidthJoiner    = '\u200D'
+)
+
+// viramas are the characters of the canonical combining class Virama (9) as of Unicode 14.0.
+var viramas = map[rune]bool{
+	0x094D: true, 0x09CD: true, 0x0A4D: true, 0x0ACD: true, 0x0B4D: true, 0x0BCD: true, 0x0C4D: true,
+	0x0CCD: true, 0x0D3B: true, 0x0D3C: true, 0x0D4D: true, 0x0DCA: true, 0x0E3A: true, 0x0EBA: true,
+	0x0F84: true, 0x1039: true, 0x103A: true, 0x1714: true, 0x1715: true, 0x1734: true, 0x17D2: true,
+	0x1A60: true, 0x1B44: true, 0x1BAA: true, 0x1BAB: true, 0x1BF2: true, 0x1BF3: true, 0x2D7F: true,
+	0xA806: true, 0xA82C: true, 0xA8C4: true, 0xA953: true, 0xA9C0: true, 0xAAF6: true, 0xABED: true,
+	0x10A3F: true, 0x11046: true, 0x11070: true, 0x1107F: true, 0x110B9: true, 0x11133: true,
+	0x11134: true, 0x111C0: true, 0x11235: true, 0x112EA: true, 0x1134D: true, 0x11442: true,
+	0x114C2: true, 0x115BF: true, 0x1163F: true, 0x116B6: true, 0x1172B: true, 0x11839: true,
+	0x1193D: true, 0x1193E: true, 0x119E0: true, 0x11A34: true, 0x11A47: true, 0x11A99: true,
+	0x11C3F: true, 0x11D44: true, 0x11D45: true, 0x11D97: true,
+}
+
+// joiningScripts are the scripts of the cursively joined letters, where ZWNJ prevents the joining.
+var joiningScripts = []*unicode.RangeTable{
+	unicode.Arabic, unicode.Syriac, unicode.Nko, unicode.Mongolian, unicode.Manichaean,
+	unicode.Psalter_Pahlavi, unicode.Adlam, unicode.Hanifi_Rohingya, unicode.Sogdian,
+}
+
+// isJoiningLetter reports whether the character is a letter of a cursive script.
+func isJoiningLetter(r rune) bool {
+	return unicode.IsLetter(r) && unicode.In(r, joiningScripts...)
+}
+
+// checkJoiners checks the zero-width joiners of the label against the CONTEXTJ rules of IDNA2008.
+// ZWJ and ZWNJ are allowed after a virama; ZWNJ is also allowed between the letters of a cursive script.
+// The joining types of the letters are approximated by their scripts, as the standard library has no such table.
+func checkJoiners(label string) string {
+	runes := []rune(label)
+	for i, r := range runes {
+		if r != zeroWidthJoiner && r != zeroWidthNonJoiner {
+			continue
+		}
+		if i > 0 && viramas[runes[i-1]] {
+			continue
+		}
+		if r == zeroWidthNonJoiner && i > 0 && i < len(runes)-1 && isJoiningLetter(runes[i-1]) && isJoiningLetter(runes[i+1]) {
+			continue
+		}
+		return "has the invisible joiner " + strconv.QuoteRuneToASCII(r) + " outside of the context allowed by IDNA2008"
+	}
+
+	return ""
+}
+
+// idnaExceptions are the characters whose IDNA2008 property differs from the one derived from their
+// general category, RFC 5892, Section 2.6. The CONTEXTO characters are allowed without checking their context.
+var idnaExceptions = map[rune]bool{
+	// PVALID.
+	0x00DF: true, 0x03C2: true, 0x06FD: true, 0x06FE: true, 0x0F0B: true, 0x3007: true,
+	// CONTEXTO.
+	0x00B7: true, 0x0375: true, 0x05F3: true, 0x05F4: true, 0x30FB: true,
+	// DISALLOWED.
+	0x0640: false, 0x07FA: false, 0x302E: false, 0x302F: false, 0x3031: false, 0x3032: false,
+	0x3033: false, 0x3034: false, 0x3035: false, 0x303B: false,
+}
+
+// checkCodePoints checks the non-ASCII characters of the lowercase label against IDNA2008.
+// Only letters, marks and decimal digits are allowed, so control, unassigned, space, punctuation
+// and symbol characters are rejected as DISALLOWED. The joiners are checked by checkJoiners.
+// The other properties of RFC 5892, e.g. the stability under NFKC, are not checked.
+func checkCodePoints(label string) string {
+	for _, r := range label {
+		if r < utf8.RuneSelf || r == zeroWidthJoiner || r == zeroWidthNonJoiner {
+			continue
+		}
+		allowed, ok := idnaExceptions[r]
+		if !ok {
+			allowed = unicode.In(r, unicode.Ll, unicode.Lo, unicode.Lm, unicode.Mn, unicode.Mc, unicode.Nd)
+		}
+		if !allowed {
+			return "has the character " + strconv.QuoteRuneToASCII(r) + " disallowed by IDNA2008"
+		}
+	}
+
+	return ""
+}
+
+// labelSeparators are the characters treated as the label separator by IDNA.
+var labelSeparators = strings.NewReplacer("。", ".", "．", ".", "｡", ".")
+
+// DomainNameError is returned when the domain name is malformed.
+type DomainNameError struct {
+	Name    string
+	Message string
+}
+
+// Error returns error message as a string.
+func (e *DomainNameError) Error() string {
+	return `invalid domain name "` + e.Name + `": ` + e.Message
+}
+
+// toASCII converts the domain name to the lowercase ASCII form, encoding Unicode labels to Punycode.
+// The trailing dot is removed. The result is validated against the hostname syntax.
+func toASCII(name string) (string, error) {
+	mapped, msg := mapName(strings.TrimSpace(name))
+	if msg != "" {
+		return "", &DomainNameError{name, msg}
+	}
+
+	domain := strings.TrimSuffix(labelSeparators.Replace(mapped), ".")
+	if domain == "" {
+		return "", &DomainNameError{name, "is empty"}
+	}
+
+	labels := strings.Split(strings.ToLower(domain), ".")
+	for i, label := range labels {
+		if isASCII(label) {
+			continue
+		}
+		if msg := checkCodePoints(label); msg != "" {
+			return "", &DomainNameError{name, msg}
+		}
+		if msg := checkJoiners(label); msg != "" {
+			return "", &DomainNameError{name, msg}
+		}
+
+		encoded, err := punycodeEncode(label)
+		if err != nil {
+			return "", &DomainNameError{name, err.Error()}
+		}
+		labels[i] = acePrefix + encoded
+	}
+
+	ascii := strings.Join(labels, ".")
+	if err := checkHostname(ascii); err != "" {
+		return "", &DomainNameError{name, err}
+	}
+
+	return ascii, nil
+}
+
+// toUnicode converts the domain name to the lowercase Unicode form, decoding Punycode labels.
+// The trailing dot is removed. The name is validated as by toASCII.
+func toUnicode(name string) (string, error) {
+	ascii, err := toASCII(name)
+	if err != nil {
+		return "", err
+	}
+
+	labels := strings.Split(ascii, ".")
+	for i, label := range labels {
+		if !strings.HasPrefix(label, acePrefix) {
+			continue
+		}
+
+		decoded, err := punycodeDecode(label[len(acePrefix):])
+		if err != nil {
+			return "", &DomainNameError{name, err.Error()}
+		}
+		decoded = strings.ToLower(decoded)
+		if msg := checkCodePoints(decoded); msg != "" {
+			return "", &DomainNameError{name, msg}
+		}
+		if msg := checkJoiners(decoded); msg != "" {
+			return "", &DomainNameError{name, msg}
+		}
+		labels[i] = decoded
+	}
+
+	return strings.Join(labels, "."), nil
+}
+
+// checkHostname validates the ASCII domain name and returns the problem description if any.
+// Underscores are accepted, as they are common in DNS names.
+func checkHostname(name string) string {
+	if len(name) > 253 {
+		return "is longer than 253 characters"
+	}
+
+	for _, label := range strings.Split(name, ".") {
+		if label == "" {
+			return "has an empty label"
+		}
+		if len(label) > 63 {
+			return "has a label longer than 63 characters"
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return "has a label starting or ending with a hyphen"
+		}
+		for i := 0; i < len(label); i++ {
+			c := label[i]
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+				return "has an invalid character " + strconv.QuoteRune(rune(c))
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,210 @@
+package registrantalert
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// KeyFunc returns the group key of the domain, e.g. its action or TLD.
+type KeyFunc func(item DomainItem) string
+
+// KeyAction groups the domains by the action.
+func KeyAction(item DomainItem) string {
+	return string(item.Action)
+}
+
+// KeyTLD groups the domains by the top-level domain in the ASCII form.
+// Malformed domain names have an empty key.
+func KeyTLD(item DomainItem) string {
+	return item.TLD()
+}
+
+// KeyDay groups the domains by the date in the YYYY-MM-DD format.
+// Domains without the date have an empty key.
+func KeyDay(item DomainItem) string {
+	if item.Date.IsZero() {
+		return ""
+	}
+	return time.Time(item.Date).Format(dateFormat)
+}
+
+// KeyRegistrableDomain groups the domains by the registrable domain in the ASCII form.
+func KeyRegistrableDomain(item DomainItem) string {
+	return item.RegistrableDomain()
+}
+
+var _ = []KeyFunc{
+	KeyAction,
+	KeyTLD,
+	KeyDay,
+	KeyRegistrableDomain,
+}
+
+// Count is the number of domains with the same group key.
+type Count struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// items returns the domains of the response. The response may be nil.
+func (r *RegistrantAlertResponse) items() []DomainItem {
+	if r == nil {
+		return nil
+	}
+	return r.DomainsList
+}
+
+// withItems returns the new response with the domains.
+func withItems(items []DomainItem) *RegistrantAlertResponse {
+	return &RegistrantAlertResponse{DomainsList: items, DomainsCount: len(items)}
+}
+
+// Filter returns the response with the domains matching the predicate.
+// The original response is not modified.
+func (r *RegistrantAlertResponse) Filter(match func(item DomainItem) bool) *RegistrantAlertResponse {
+	items := []DomainItem{}
+	for _, item := range r.items() {
+		if match(item) {
+			items = append(items, item)
+		}
+	}
+	return withItems(items)
+}
+
+// FilterAction returns the response with the domains having one of the actions.
+func (r *RegistrantAlertResponse) FilterAction(actions ...Action) *RegistrantAlertResponse {
+	return r.Filter(func(item DomainItem) bool {
+		for _, action := range actions {
+			if item.Action == action {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// FilterDateRange returns the response with the domains dated from one date to another inclusive.
+// Only the dates are compared. A zero bound is not checked. Domains without the date are excluded.
+func (r *RegistrantAlertResponse) FilterDateRange(from, to time.Time) *RegistrantAlertResponse {
+	var fromDay, toDay string
+	if !from.IsZero() {
+		fromDay = from.Format(dateFormat)
+	}
+	if !to.IsZero() {
+		toDay = to.Format(dateFormat)
+	}
+
+	return r.Filter(func(item DomainItem) bool {
+		day := KeyDay(item)
+		return day != "" && (fromDay == "" || day >= fromDay) && (toDay == "" || day <= toDay)
+	})
+}
+
+// FilterTLD returns the response with the domains in one of the top-level domains.
+// The TLDs may be in the Unicode or ASCII form and start with a dot.
+func (r *RegistrantAlertResponse) FilterTLD(tlds ...string) *RegistrantAlertResponse {
+	set := make(map[string]bool)
+	for _, tld := range tlds {
+		if ascii, err := toASCII(strings.TrimPrefix(tld, ".")); err == nil {
+			set[ascii] = true
+		}
+	}
+
+	return r.Filter(func(item DomainItem) bool {
+		return set[KeyTLD(item)]
+	})
+}
+
+// FilterRegexp returns the response with the domain names matching the regular expression.
+func (r *RegistrantAlertResponse) FilterRegexp(re *regexp.Regexp) *RegistrantAlertResponse {
+	return r.Filter(func(item DomainItem) bool {
+		return re.MatchString(item.DomainName)
+	})
+}
+
+// GroupBy returns the domains by the group key.
+func (r *RegistrantAlertResponse) GroupBy(key KeyFunc) map[string][]DomainItem {
+	groups := make(map[string][]DomainItem)
+	for _, item := range r.items() {
+		k := key(item)
+		groups[k] = append(groups[k], item)
+	}
+	return groups
+}
+
+// CountBy returns the number of domains by the group key, the largest groups first.
+// Groups of the same size are sorted by the key.
+func (r *RegistrantAlertResponse) CountBy(key KeyFunc) []Count {
+	counts := make(map[string]int)
+	for _, item := range r.items() {
+		counts[key(item)]++
+	}
+
+	result := make([]Count, 0, len(counts))
+	for k, n := range counts {
+		result = append(result, Count{Key: k, Count: n})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Key < result[j].Key
+	})
+
+	return result
+}
+
+// Top returns at most n largest groups by the key.
+func (r *RegistrantAlertResponse) Top(n int, key KeyFunc) []Count {
+	counts := r.CountBy(key)
+	if n >= 0 && len(counts) > n {
+		counts = counts[:n]
+	}
+	return counts
+}
+
+// Sort returns the response with the domains sorted by the less function.
+// The sort is stable and the original response is not modified.
+func (r *RegistrantAlertResponse) Sort(less func(a, b DomainItem) bool) *RegistrantAlertResponse {
+	items := append([]DomainItem{}, r.items()...)
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return less(items[i], items[j])
+	})
+
+	return withItems(items)
+}
+
+// SortByDate returns the response with the domains sorted from the oldest to the newest,
+// then by the name. Domains without the date go first.
+func (r *RegistrantAlertResponse) SortByDate() *RegistrantAlertResponse {
+	return r.Sort(func(a, b DomainItem) bool {
+		ta, tb := time.Time(a.Date), time.Time(b.Date)
+		if !ta.Equal(tb) {
+			return ta.Before(tb)
+		}
+		return a.DomainName < b.DomainName
+	})
+}
+
+// SortByName returns the response with the domains sorted by the name.
+func (r *RegistrantAlertResponse) SortByName() *RegistrantAlertResponse {
+	return r.Sort(func(a, b DomainItem) bool {
+		return a.DomainName < b.DomainName
+	})
+}
+
+// Reverse returns the response with the domains in the reverse order.
+func (r *RegistrantAlertResponse) Reverse() *RegistrantAlertResponse {
+	items := r.items()
+
+	reversed := make([]DomainItem, len(items))
+	for i, item := range items {
+		reversed[len(items)-1-i] = item
+	}
+
+	return withItems(reversed)
+}
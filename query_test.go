@@ -0,0 +1,107 @@
+package registrantalert
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// testQueryResponse returns the sample response for the query tests.
+func testQueryResponse() *RegistrantAlertResponse {
+	day := func(d int) Time {
+		return Time(time.Date(2022, 10, d, 0, 0, 0, 0, time.UTC))
+	}
+
+	return withItems([]DomainItem{
+		{"whoisxmlapi.com", Added, day(28)},
+		{"whoisxmlapi.net", Added, day(30)},
+		{"batchwhois.com", Dropped, day(29)},
+		{"whoislookup.info", Updated, day(28)},
+		{"bücher.de", Discovered, emptyTime},
+	})
+}
+
+// domainNames returns the domain names of the response.
+func domainNames(r *RegistrantAlertResponse) []string {
+	list := []string{}
+	for _, item := range r.DomainsList {
+		list = append(list, item.DomainName)
+	}
+	return list
+}
+
+// TestResponseFilter tests the filters.
+func TestResponseFilter(t *testing.T) {
+	resp := testQueryResponse()
+
+	tests := []struct {
+		name string
+		got  *RegistrantAlertResponse
+		want []string
+	}{
+		{"action", resp.FilterAction(Added, Dropped),
+			[]string{"whoisxmlapi.com", "whoisxmlapi.net", "batchwhois.com"}},
+		{"date range", resp.FilterDateRange(time.Date(2022, 10, 29, 0, 0, 0, 0, time.UTC), time.Date(2022, 10, 30, 23, 0, 0, 0, time.UTC)),
+			[]string{"whoisxmlapi.net", "batchwhois.com"}},
+		{"date from", resp.FilterDateRange(time.Date(2022, 10, 30, 0, 0, 0, 0, time.UTC), time.Time{}),
+			[]string{"whoisxmlapi.net"}},
+		{"tld", resp.FilterTLD(".COM", "de"),
+			[]string{"whoisxmlapi.com", "batchwhois.com", "bücher.de"}},
+		{"regexp", resp.FilterRegexp(regexp.MustCompile(`^whois`)),
+			[]string{"whoisxmlapi.com", "whoisxmlapi.net", "whoislookup.info"}},
+		{"chained", resp.FilterAction(Added).FilterTLD("net"),
+			[]string{"whoisxmlapi.net"}},
+		{"nil response", (*RegistrantAlertResponse)(nil).FilterAction(Added),
+			[]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := domainNames(tt.got); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if tt.got.DomainsCount != len(tt.want) {
+				t.Errorf("DomainsCount = %d, want %d", tt.got.DomainsCount, len(tt.want))
+			}
+		})
+	}
+
+	if len(resp.DomainsList) != 5 {
+		t.Errorf("original response is modified")
+	}
+}
+
+// TestResponseAggregation tests the grouping, counting and sorting.
+func TestResponseAggregation(t *testing.T) {
+	resp := testQueryResponse()
+
+	wantCounts := []Count{{"com", 2}, {"de", 1}, {"info", 1}, {"net", 1}}
+	if got := resp.CountBy(KeyTLD); !reflect.DeepEqual(got, wantCounts) {
+		t.Errorf("CountBy(KeyTLD) = %v, want %v", got, wantCounts)
+	}
+
+	wantTop := []Count{{"added", 2}, {"discovered", 1}}
+	if got := resp.Top(2, KeyAction); !reflect.DeepEqual(got, wantTop) {
+		t.Errorf("Top(2, KeyAction) = %v, want %v", got, wantTop)
+	}
+
+	groups := resp.GroupBy(KeyDay)
+	if len(groups) != 4 || len(groups["2022-10-28"]) != 2 || len(groups[""]) != 1 {
+		t.Errorf("GroupBy(KeyDay) = %v", groups)
+	}
+
+	wantByDate := []string{"bücher.de", "whoislookup.info", "whoisxmlapi.com", "batchwhois.com", "whoisxmlapi.net"}
+	if got := domainNames(resp.SortByDate()); !reflect.DeepEqual(got, wantByDate) {
+		t.Errorf("SortByDate() = %v, want %v", got, wantByDate)
+	}
+
+	wantByName := []string{"whoisxmlapi.net", "whoisxmlapi.com", "whoislookup.info", "bücher.de", "batchwhois.com"}
+	if got := domainNames(resp.SortByName().Reverse()); !reflect.DeepEqual(got, wantByName) {
+		t.Errorf("SortByName().Reverse() = %v, want %v", got, wantByName)
+	}
+
+	if domainNames(resp)[0] != "whoisxmlapi.com" {
+		t.Errorf("original response is modified")
+	}
+}
@@ -5,9 +5,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"mime"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // RegistrantAlert is an interface for Registrant Alert API.
@@ -19,6 +22,7 @@ type RegistrantAlert interface {
 	BasicPurchase(ctx context.Context, basicSearchTerms *BasicSearchTerms, option ...Option) (*RegistrantAlertResponse, *Response, error)
 
 	// BasicRawData returns raw Registrant Alert API response for the basic search.
+	// Response.Format reports the format of the returned body.
 	BasicRawData(ctx context.Context, basicSearchTerms *BasicSearchTerms, option ...Option) (*Response, error)
 
 	// AdvancedPreview returns only the number of domains for the advanced search. No credits deducted.
@@ -28,6 +32,7 @@ type RegistrantAlert interface {
 	AdvancedPurchase(ctx context.Context, advancedSearchTerms []AdvancedSearchTerm, option ...Option) (*RegistrantAlertResponse, *Response, error)
 
 	// AdvancedRawData returns raw Registrant Alert API response for the advanced search.
+	// Response.Format reports the format of the returned body.
 	AdvancedRawData(ctx context.Context, advancedSearchTerms []AdvancedSearchTerm, option ...Option) (*Response, error)
 }
 
@@ -39,6 +44,35 @@ type Response struct {
 	Body []byte
 }
 
+// ContentType returns the media type of the response body without parameters, e.g. "application/json".
+func (r *Response) ContentType() string {
+	if r == nil || r.Response == nil {
+		return ""
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+
+	return mediaType
+}
+
+// Format returns the format of the response body according to its Content-Type.
+// It returns an empty string if the Content-Type is neither JSON nor XML.
+func (r *Response) Format() ResponseFormat {
+	mediaType := r.ContentType()
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return JSON
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return XML
+	}
+
+	return ""
+}
+
 // registrantAlertServiceOp is the type implementing the RegistrantAlert interface.
 type registrantAlertServiceOp struct {
 	client  *Client
@@ -114,6 +148,94 @@ func validateOptions(opts ...Option) error {
 	return nil
 }
 
+// validateDefaultOptions validates the client-wide default options.
+func validateDefaultOptions(opts ...Option) error {
+	for _, opt := range opts {
+		if opt == nil {
+			return &ArgError{"DefaultOptions", "can not contain nil"}
+		}
+	}
+	return nil
+}
+
+// sinceDateLookBackMonths is how far in the past the API allows SinceDate to be.
+// The API searches through the activities of the last 12 months, see the sinceDate parameter in
+// https://registrant-alert.whoisxmlapi.com/api/documentation/making-requests
+const sinceDateLookBackMonths = 12
+
+// zeroDate is the zero time.Time formatted as a date.
+var zeroDate = time.Time{}.Format(dateFormat)
+
+// validateRequest validates the request after all options are applied.
+// The dates are checked against today's date unless it's zero.
+func validateRequest(request *registrantAlertRequest, today time.Time) error {
+	if !request.ResponseFormat.IsValid() {
+		return &ArgError{"responseFormat", "must be json or xml."}
+	}
+
+	dates := []struct {
+		name  string
+		value string
+	}{
+		{"sinceDate", request.SinceDate},
+		{"createdDateFrom", request.CreatedDateFrom},
+		{"createdDateTo", request.CreatedDateTo},
+		{"updatedDateFrom", request.UpdatedDateFrom},
+		{"updatedDateTo", request.UpdatedDateTo},
+		{"expiredDateFrom", request.ExpiredDateFrom},
+		{"expiredDateTo", request.ExpiredDateTo},
+	}
+
+	parsed := make(map[string]time.Time, len(dates))
+	for _, date := range dates {
+		if date.value == "" {
+			continue
+		}
+		if date.value == zeroDate {
+			return &ArgError{date.name, "must not be the zero date."}
+		}
+
+		t, err := time.Parse(dateFormat, date.value)
+		if err != nil {
+			return &ArgError{date.name, "must be a date in YYYY-MM-DD format."}
+		}
+		parsed[date.name] = t
+	}
+
+	ranges := [][2]string{
+		{"createdDateFrom", "createdDateTo"},
+		{"updatedDateFrom", "updatedDateTo"},
+		{"expiredDateFrom", "expiredDateTo"},
+	}
+	for _, r := range ranges {
+		from, okFrom := parsed[r[0]]
+		to, okTo := parsed[r[1]]
+		if okFrom && okTo && from.After(to) {
+			return &ArgError{r[0], "must not be after " + r[1] + "."}
+		}
+	}
+
+	if today.IsZero() {
+		return nil
+	}
+
+	// The dates are compared in UTC with a day of tolerance for the callers' time zones.
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	tomorrow := today.AddDate(0, 0, 1)
+
+	for _, name := range []string{"sinceDate", "createdDateFrom", "updatedDateFrom"} {
+		if t, ok := parsed[name]; ok && t.After(tomorrow) {
+			return &ArgError{name, "must not be in the future."}
+		}
+	}
+
+	if t, ok := parsed["sinceDate"]; ok && t.Before(today.AddDate(0, -sinceDateLookBackMonths, -1)) {
+		return &ArgError{"sinceDate", "must be within the last " + strconv.Itoa(sinceDateLookBackMonths) + " months."}
+	}
+
+	return nil
+}
+
 // request returns intermediate API response for further actions.
 func (service registrantAlertServiceOp) request(
 	ctx context.Context,
@@ -121,44 +243,57 @@ func (service registrantAlertServiceOp) request(
 	advancedSearchTerms []AdvancedSearchTerm,
 	purchase bool,
 	opts ...Option) (*Response, error) {
-	var request = &registrantAlertRequest{
-		service.client.apiKey,
-		basicSearchTerms,
-		advancedSearchTerms,
-		"",
-		"preview",
-		true,
-		"json",
-		"",
-		"",
-		"",
-		"",
-		"",
-		"",
+	if err := validateDefaultOptions(service.client.defaultOptions...); err != nil {
+		return nil, err
 	}
 
-	if purchase {
-		request.Mode = "purchase"
+	if err := validateOptions(opts...); err != nil {
+		return nil, err
 	}
 
-	if err := validateOptions(opts...); err != nil {
+	// The API defaults go first, then the client-wide defaults, so the per-call options override them.
+	all := make([]Option, 0, len(service.client.defaultOptions)+len(opts)+2)
+	all = append(all, OptionPunycode(true), OptionResponseFormat(JSON))
+	all = append(all, service.client.defaultOptions...)
+	all = append(all, opts...)
+
+	params, err := ParamsFromOptions(all...)
+	if err != nil {
 		return nil, err
 	}
 
-	for _, opt := range opts {
-		opt(request)
+	request := &Request{
+		BasicSearchTerms:    basicSearchTerms,
+		AdvancedSearchTerms: advancedSearchTerms,
+		Mode:                RunPreview,
+		Params:              params,
+	}
+
+	if purchase {
+		request.Mode = RunPurchase
+	}
+
+	if err := request.validate(service.client.today()); err != nil {
+		return nil, err
 	}
 
-	requestBody, err := json.Marshal(request)
+	requestBody, err := encodeRequest(request, service.client.apiKey)
 	if err != nil {
 		return nil, err
 	}
 
+	return service.send(ctx, requestBody, request.responseFormat())
+}
+
+// send posts the request body and returns the raw API response in the format.
+func (service registrantAlertServiceOp) send(ctx context.Context, requestBody []byte, format ResponseFormat) (*Response, error) {
 	req, err := service.newRequest(requestBody)
 	if err != nil {
 		return nil, err
 	}
 
+	req.Header.Set("Accept", format.mediaType())
+
 	var b bytes.Buffer
 
 	resp, err := service.client.Do(ctx, req, &b)
@@ -199,7 +334,7 @@ func (service registrantAlertServiceOp) BasicPurchase(
 	}
 	optsJSON := make([]Option, 0, len(opts)+1)
 	optsJSON = append(optsJSON, opts...)
-	optsJSON = append(optsJSON, OptionResponseFormat("json"))
+	optsJSON = append(optsJSON, OptionResponseFormat(JSON))
 
 	resp, err = service.request(ctx, basicSearchTerms, nil, true, optsJSON...)
 	if err != nil {
@@ -234,7 +369,7 @@ func (service registrantAlertServiceOp) BasicPreview(
 
 	optsJSON := make([]Option, 0, len(opts)+1)
 	optsJSON = append(optsJSON, opts...)
-	optsJSON = append(optsJSON, OptionResponseFormat("json"))
+	optsJSON = append(optsJSON, OptionResponseFormat(JSON))
 
 	resp, err = service.request(ctx, basicSearchTerms, nil, false, optsJSON...)
 	if err != nil {
@@ -292,7 +427,7 @@ func (service registrantAlertServiceOp) AdvancedPreview(
 
 	optsJSON := make([]Option, 0, len(opts)+1)
 	optsJSON = append(optsJSON, opts...)
-	optsJSON = append(optsJSON, OptionResponseFormat("json"))
+	optsJSON = append(optsJSON, OptionResponseFormat(JSON))
 
 	resp, err = service.request(ctx, nil, advancedSearchTerms, false, optsJSON...)
 	if err != nil {
@@ -326,7 +461,7 @@ func (service registrantAlertServiceOp) AdvancedPurchase(
 	}
 	optsJSON := make([]Option, 0, len(opts)+1)
 	optsJSON = append(optsJSON, opts...)
-	optsJSON = append(optsJSON, OptionResponseFormat("json"))
+	optsJSON = append(optsJSON, OptionResponseFormat(JSON))
 
 	resp, err = service.request(ctx, nil, advancedSearchTerms, true, optsJSON...)
 	if err != nil {
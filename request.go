@@ -0,0 +1,233 @@
+package registrantalert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Request is the Registrant Alert API request without the API key.
+// Its JSON form is the wire payload of the API, see MarshalRequest.
+// Parameters that are not set are left to the API defaults.
+type Request struct {
+	// BasicSearchTerms is the set of search terms for the Basic search.
+	BasicSearchTerms *BasicSearchTerms
+
+	// AdvancedSearchTerms is the set of search terms for the Advanced search.
+	AdvancedSearchTerms []AdvancedSearchTerm
+
+	// Mode is the mode of the API call: preview | purchase. Default: preview.
+	Mode RunMode
+
+	// Params are the optional parameters of the request. They are encoded inline with the other fields.
+	Params RequestParams
+}
+
+// requestJSON is the JSON form of Request with the parameters inline.
+type requestJSON struct {
+	BasicSearchTerms    *BasicSearchTerms    `json:"basicSearchTerms,omitempty"`
+	AdvancedSearchTerms []AdvancedSearchTerm `json:"advancedSearchTerms,omitempty"`
+	Mode                RunMode              `json:"mode,omitempty"`
+	RequestParams
+}
+
+// wireRequest is the wire payload of the API with the API key.
+type wireRequest struct {
+	APIKey string `json:"apiKey,omitempty"`
+	requestJSON
+}
+
+// toJSON returns the JSON form of the request.
+func (r *Request) toJSON() requestJSON {
+	return requestJSON{
+		BasicSearchTerms:    r.BasicSearchTerms,
+		AdvancedSearchTerms: r.AdvancedSearchTerms,
+		Mode:                r.Mode,
+		RequestParams:       r.Params,
+	}
+}
+
+// request returns the request of the JSON form.
+func (v requestJSON) request() *Request {
+	return &Request{
+		BasicSearchTerms:    v.BasicSearchTerms,
+		AdvancedSearchTerms: v.AdvancedSearchTerms,
+		Mode:                v.Mode,
+		Params:              v.RequestParams,
+	}
+}
+
+// MarshalJSON encodes the request with the parameters inline. The API key is not included.
+func (r Request) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.toJSON())
+}
+
+// UnmarshalJSON decodes the request with the parameters inline.
+func (r *Request) UnmarshalJSON(b []byte) error {
+	var v requestJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*r = *v.request()
+	return nil
+}
+
+// NewBasicRequest builds the request of the basic search from the options.
+func NewBasicRequest(basicSearchTerms *BasicSearchTerms, mode RunMode, opts ...Option) (*Request, error) {
+	return newRequest(&Request{BasicSearchTerms: basicSearchTerms, Mode: mode}, opts)
+}
+
+// NewAdvancedRequest builds the request of the advanced search from the options.
+func NewAdvancedRequest(advancedSearchTerms []AdvancedSearchTerm, mode RunMode, opts ...Option) (*Request, error) {
+	return newRequest(&Request{AdvancedSearchTerms: advancedSearchTerms, Mode: mode}, opts)
+}
+
+// newRequest sets the parameters of the options to the request and validates it.
+func newRequest(r *Request, opts []Option) (*Request, error) {
+	params, err := ParamsFromOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	r.Params = params
+
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Validate checks the search terms, the mode and the parameters.
+// The dates are checked against the current date only when the request is sent, see Client.SendRequest.
+func (r *Request) Validate() error {
+	return r.validate(time.Time{})
+}
+
+// validate checks the request. The dates are checked against today's date unless it's zero.
+func (r *Request) validate(today time.Time) error {
+	switch {
+	case r.BasicSearchTerms != nil && r.AdvancedSearchTerms != nil:
+		return &ArgError{"searchTerms", "must not have both basicSearchTerms and advancedSearchTerms."}
+	case r.BasicSearchTerms != nil:
+		if err := validateBasicSearchTerms(r.BasicSearchTerms); err != nil {
+			return err
+		}
+	case r.AdvancedSearchTerms != nil:
+		if err := validateAdvancedSearchTerms(r.AdvancedSearchTerms); err != nil {
+			return err
+		}
+	default:
+		return &ArgError{"searchTerms", "must have basicSearchTerms or advancedSearchTerms."}
+	}
+
+	if r.Mode != "" && !r.Mode.IsValid() {
+		return &ArgError{"mode", "must be preview or purchase."}
+	}
+
+	opts, err := r.Params.Options()
+	if err != nil {
+		return err
+	}
+
+	request := &registrantAlertRequest{ResponseFormat: JSON}
+	for _, opt := range opts {
+		opt(request)
+	}
+
+	return validateRequest(request, today)
+}
+
+// responseFormat returns the requested response format or the API default.
+func (r *Request) responseFormat() ResponseFormat {
+	format, err := ParseResponseFormat(string(r.Params.ResponseFormat))
+	if err != nil {
+		return JSON
+	}
+	return format
+}
+
+// MarshalRequest validates the request and encodes it with the API key to the wire payload.
+// The API key is omitted if empty.
+func MarshalRequest(r *Request, apiKey string) ([]byte, error) {
+	if r == nil {
+		return nil, &ArgError{"request", "is required."}
+	}
+
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	return encodeRequest(r, apiKey)
+}
+
+// encodeRequest encodes the request with the API key to the wire payload.
+// The response format is sent in lower case as the API expects.
+func encodeRequest(r *Request, apiKey string) ([]byte, error) {
+	w := wireRequest{APIKey: apiKey, requestJSON: r.toJSON()}
+	if w.ResponseFormat != "" {
+		w.ResponseFormat = r.responseFormat()
+	}
+
+	b, err := json.Marshal(w)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode request: %w", err)
+	}
+
+	return b, nil
+}
+
+// UnmarshalRequest decodes and validates the wire payload.
+// It returns the request and the API key separately. The API key may be empty.
+func UnmarshalRequest(data []byte) (*Request, string, error) {
+	var w wireRequest
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&w); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			return nil, "", &ArgError{typeErr.Field, "must be " + typeErr.Type.String() + "."}
+		}
+
+		return nil, "", fmt.Errorf("cannot parse request: %w", err)
+	}
+
+	r := w.request()
+	if err := r.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	return r, w.APIKey, nil
+}
+
+// errNoService is returned when the client does not use the API service.
+var errNoService = errors.New("cannot send request: client has a custom RegistrantAlert implementation")
+
+// SendRequest sends the pre-built request with the API key of the client and returns the raw response.
+// The request is sent as is, the default options of the client are not applied.
+// The dates are checked against the client's calendar.
+func (c *Client) SendRequest(ctx context.Context, r *Request) (*Response, error) {
+	service, ok := c.RegistrantAlert.(*registrantAlertServiceOp)
+	if !ok {
+		return nil, errNoService
+	}
+
+	if r == nil {
+		return nil, &ArgError{"request", "is required."}
+	}
+
+	if err := r.validate(c.today()); err != nil {
+		return nil, err
+	}
+
+	body, err := encodeRequest(r, c.apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.send(ctx, body, r.responseFormat())
+}
@@ -0,0 +1,180 @@
+package registrantalert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMarshalRequest tests the wire payload of the request and its decoding.
+func TestMarshalRequest(t *testing.T) {
+	t.Parallel()
+
+	r, err := NewBasicRequest(&BasicSearchTerms{Include: []string{"whoisxmlapi"}}, RunPurchase,
+		OptionPunycode(false), OptionSinceDate(time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := MarshalRequest(r, apiKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"apiKey":"` + apiKey + `","basicSearchTerms":{"include":["whoisxmlapi"]},"mode":"purchase",` +
+		`"punycode":false,"sinceDate":"2022-10-01"}`
+	if string(b) != want {
+		t.Errorf("MarshalRequest() = %s, want %s", b, want)
+	}
+
+	got, key, err := UnmarshalRequest(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != apiKey || !reflect.DeepEqual(got, r) {
+		t.Errorf("UnmarshalRequest() = %+v, %q, want %+v", got, key, r)
+	}
+
+	b, err = MarshalRequest(r, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, key, err = UnmarshalRequest(b); err != nil || key != "" {
+		t.Errorf("UnmarshalRequest() without API key = %q, %v", key, err)
+	}
+
+	if got := fmt.Sprintf("%v", *r); !strings.Contains(got, "purchase") {
+		t.Errorf("formatted request = %s, want the mode", got)
+	}
+
+	var decoded Request
+	if err := json.Unmarshal(b, &decoded); err != nil || !reflect.DeepEqual(&decoded, r) {
+		t.Errorf("json.Unmarshal() = %+v, %v, want %+v", decoded, err, r)
+	}
+
+	upper := *r
+	upper.Params.ResponseFormat = " XML"
+	b, err = MarshalRequest(&upper, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"responseFormat":"xml"`; !strings.Contains(string(b), want) {
+		t.Errorf("MarshalRequest() = %s, want %s", b, want)
+	}
+}
+
+// TestRequestValidate tests the validation of the request.
+func TestRequestValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		payload string
+		want    string
+	}{
+		{"advanced", `{"advancedSearchTerms":[{"field":"DomainName","term":"whois"}],"responseFormat":"xml"}`, ""},
+		{"no terms", `{"apiKey":"key"}`,
+			`invalid argument: "searchTerms" must have basicSearchTerms or advancedSearchTerms.`},
+		{"invalid terms", `{"basicSearchTerms":{"include":[]}}`,
+			`invalid argument: "basicSearchTerms.include" must have between 1 and 4 items.`},
+		{"invalid mode", `{"basicSearchTerms":{"include":["whois"]},"mode":"free"}`,
+			`invalid argument: "mode" must be preview or purchase.`},
+		{"invalid format", `{"basicSearchTerms":{"include":["whois"]},"responseFormat":"csv"}`,
+			`invalid argument: "responseFormat" must be json or xml.`},
+		{"invalid date", `{"basicSearchTerms":{"include":["whois"]},"sinceDate":"2022-13-01"}`,
+			`invalid argument: "sinceDate" must be a date in YYYY-MM-DD format.`},
+		{"old since date", `{"basicSearchTerms":{"include":["whois"]},"sinceDate":"2020-01-01"}`, ""},
+		{"unknown field", `{"basicSearchTerms":{"include":["whois"]},"outputFormat":"json"}`,
+			`cannot parse request: json: unknown field "outputFormat"`},
+		{"wrong type", `{"punycode":"no"}`, `invalid argument: "punycode" must be bool.`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := UnmarshalRequest([]byte(tt.payload))
+			checkErr(t, err, tt.want)
+		})
+	}
+
+	_, err := MarshalRequest(nil, apiKey)
+	checkErr(t, err, `invalid argument: "request" is required.`)
+
+	_, err = NewAdvancedRequest(nil, RunPreview)
+	checkErr(t, err, `invalid argument: "searchTerms" must have basicSearchTerms or advancedSearchTerms.`)
+}
+
+// TestClientSendRequest tests sending the pre-built request.
+func TestClientSendRequest(t *testing.T) {
+	t.Parallel()
+
+	var gotBody, gotAccept string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		gotBody, gotAccept = string(b), req.Header.Get("Accept")
+		_, _ = w.Write([]byte(`<domainsCount>1</domainsCount>`))
+	}))
+	defer server.Close()
+
+	apiURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient(apiKey, ClientParams{
+		HTTPClient:             server.Client(),
+		RegistrantAlertBaseURL: apiURL,
+		Calendar:               fixedCalendar(time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)),
+		DefaultOptions:         []Option{OptionSinceDate(time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC))},
+	})
+
+	r, _, err := UnmarshalRequest([]byte(`{"apiKey":"other","advancedSearchTerms":[{"field":"DomainName","term":"whois"}],"responseFormat":"XML"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.SendRequest(context.Background(), r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantBody := `{"apiKey":"` + apiKey + `","advancedSearchTerms":[{"field":"DomainName","term":"whois"}],"responseFormat":"xml"}`
+	if gotBody != wantBody {
+		t.Errorf("request body = %s, want %s", gotBody, wantBody)
+	}
+	if gotAccept != "application/xml" {
+		t.Errorf("Accept = %q, want application/xml", gotAccept)
+	}
+	if string(resp.Body) != `<domainsCount>1</domainsCount>` {
+		t.Errorf("response body = %s", resp.Body)
+	}
+
+	old := *r
+	old.Params.SinceDate = "2021-01-01"
+	_, err = client.SendRequest(context.Background(), &old)
+	checkErr(t, err, `invalid argument: "sinceDate" must be within the last 12 months.`)
+
+	_, _, _ = client.BasicPurchase(context.Background(), &BasicSearchTerms{Include: []string{"whois"}}, OptionPunycode(false))
+	want, err := NewBasicRequest(&BasicSearchTerms{Include: []string{"whois"}}, RunPurchase, OptionPunycode(false),
+		OptionResponseFormat(JSON), OptionSinceDate(time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wantBody, _ := MarshalRequest(want, apiKey); gotBody != string(wantBody) {
+		t.Errorf("BasicPurchase() request body = %s, want %s", gotBody, wantBody)
+	}
+
+	custom := &Client{RegistrantAlert: struct{ RegistrantAlert }{client.RegistrantAlert}}
+	_, err = custom.SendRequest(context.Background(), r)
+	checkErr(t, err, errNoService.Error())
+}
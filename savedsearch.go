@@ -0,0 +1,253 @@
+package registrantalert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// RunMode is the mode of the saved search run: preview | purchase.
+type RunMode string
+
+const (
+	// RunPreview returns only the number of domains. No credits deducted.
+	RunPreview RunMode = "preview"
+
+	// RunPurchase returns the list of domains. The credits are deducted.
+	RunPurchase RunMode = "purchase"
+)
+
+var _ = []RunMode{
+	RunPreview,
+	RunPurchase,
+}
+
+// IsValid reports whether the mode is supported.
+func (m RunMode) IsValid() bool {
+	return m == RunPreview || m == RunPurchase
+}
+
+// SavedSearch is the named search with its options in the serializable form.
+// It's stored as JSON, e.g.
+// {"name": "brand", "basicSearchTerms": {"include": ["whoisxmlapi"]}, "options": {"sinceDate": "2022-10-01"}}.
+type SavedSearch struct {
+	// Search is the name and the search terms. Exactly one of the Basic or Advanced terms is set.
+	Search
+
+	// Options are the request options of the search.
+	Options RequestParams `json:"options,omitempty"`
+
+	// SinceLast searches through activities discovered during the last duration, e.g. "168h".
+	// The date is computed when the search runs. It must not be set with options.sinceDate.
+	SinceLast Duration `json:"sinceLast,omitempty"`
+
+	// CreatedWithin searches through domains created during the last duration.
+	// The dates are computed when the search runs. It must not be set with options.createdDateFrom or createdDateTo.
+	CreatedWithin Duration `json:"createdWithin,omitempty"`
+
+	// UpdatedWithin searches through domains updated during the last duration.
+	// The dates are computed when the search runs. It must not be set with options.updatedDateFrom or updatedDateTo.
+	UpdatedWithin Duration `json:"updatedWithin,omitempty"`
+
+	// ExpiredWithin searches through domains expired during the last duration, or in the next one if it's negative.
+	// The dates are computed when the search runs. It must not be set with options.expiredDateFrom or expiredDateTo.
+	ExpiredWithin Duration `json:"expiredWithin,omitempty"`
+}
+
+// Validate checks the name, the search terms and the options.
+func (s SavedSearch) Validate() error {
+	return s.validate("savedSearch.")
+}
+
+// validate checks the saved search prefixing the argument names of the errors.
+func (s SavedSearch) validate(prefix string) error {
+	if s.Name == "" {
+		return &ArgError{prefix + "name", "is required."}
+	}
+
+	var err error
+	switch {
+	case s.BasicSearchTerms != nil && s.AdvancedSearchTerms != nil:
+		return &ArgError{prefix + "searchTerms", "must not have both basicSearchTerms and advancedSearchTerms."}
+	case s.BasicSearchTerms != nil:
+		err = validateBasicSearchTerms(s.BasicSearchTerms)
+	case s.AdvancedSearchTerms != nil:
+		err = validateAdvancedSearchTerms(s.AdvancedSearchTerms)
+	default:
+		return &ArgError{prefix + "searchTerms", "must have basicSearchTerms or advancedSearchTerms."}
+	}
+
+	if err == nil {
+		err = s.validateRelative(prefix)
+	}
+	if err == nil {
+		_, err = s.Options.Options()
+		prefix += "options."
+	}
+
+	var argErr *ArgError
+	if errors.As(err, &argErr) {
+		return &ArgError{prefix + argErr.Name, argErr.Message}
+	}
+
+	return err
+}
+
+// validateRelative checks that the relative dates are not negative and do not conflict with the saved dates.
+func (s SavedSearch) validateRelative(prefix string) error {
+	relative := []struct {
+		name     string
+		value    Duration
+		past     bool
+		conflict bool
+		dates    string
+	}{
+		{"sinceLast", s.SinceLast, true, s.Options.SinceDate != "", "sinceDate"},
+		{"createdWithin", s.CreatedWithin, true, s.Options.CreatedDateFrom != "" || s.Options.CreatedDateTo != "",
+			"createdDateFrom or createdDateTo"},
+		{"updatedWithin", s.UpdatedWithin, true, s.Options.UpdatedDateFrom != "" || s.Options.UpdatedDateTo != "",
+			"updatedDateFrom or updatedDateTo"},
+		{"expiredWithin", s.ExpiredWithin, false, s.Options.ExpiredDateFrom != "" || s.Options.ExpiredDateTo != "",
+			"expiredDateFrom or expiredDateTo"},
+	}
+
+	for _, r := range relative {
+		switch {
+		case r.value == 0:
+		case r.past && r.value < 0:
+			return &ArgError{r.name, "must not be negative."}
+		case r.conflict:
+			return &ArgError{r.name, "must not be set with " + prefix + "options." + r.dates + "."}
+		}
+	}
+
+	return nil
+}
+
+// relativeOptions returns the options of the relative dates computed with the calendar.
+func (s SavedSearch) relativeOptions(cal Calendar) []Option {
+	var opts []Option
+
+	if s.SinceLast != 0 {
+		opts = append(opts, cal.OptionSinceLast(time.Duration(s.SinceLast)))
+	}
+	if s.CreatedWithin != 0 {
+		opts = append(opts, cal.OptionCreatedWithin(time.Duration(s.CreatedWithin), 0))
+	}
+	if s.UpdatedWithin != 0 {
+		opts = append(opts, cal.OptionUpdatedWithin(time.Duration(s.UpdatedWithin), 0))
+	}
+	if s.ExpiredWithin > 0 {
+		opts = append(opts, cal.OptionExpiredWithin(time.Duration(s.ExpiredWithin), 0))
+	}
+	if s.ExpiredWithin < 0 {
+		opts = append(opts, cal.OptionExpiredWithin(0, time.Duration(s.ExpiredWithin)))
+	}
+
+	return opts
+}
+
+// Run executes the saved search against the API. The per-call options override the saved ones.
+// The relative dates are computed with the calendar of the client, or DefaultCalendar if api is not a *Client.
+// In the preview mode the result response has only the number of domains.
+func (s SavedSearch) Run(ctx context.Context, api RegistrantAlert, mode RunMode, opts ...Option) (SearchResult, *Response, error) {
+	result := SearchResult{Search: s.Search, RunAt: time.Now()}
+
+	if !mode.IsValid() {
+		return result, nil, &ArgError{"mode", "must be preview or purchase."}
+	}
+
+	if err := s.Validate(); err != nil {
+		return result, nil, err
+	}
+
+	saved, err := s.Options.Options()
+	if err != nil {
+		return result, nil, err
+	}
+	cal := DefaultCalendar
+	if client, ok := api.(*Client); ok {
+		cal = client.clientCalendar()
+	}
+	saved = append(saved, s.relativeOptions(cal)...)
+	opts = append(saved, opts...)
+
+	var resp *Response
+
+	switch {
+	case mode == RunPreview && s.BasicSearchTerms != nil:
+		var count int
+		count, resp, err = api.BasicPreview(ctx, s.BasicSearchTerms, opts...)
+		result.Response = &RegistrantAlertResponse{DomainsCount: count}
+	case mode == RunPreview:
+		var count int
+		count, resp, err = api.AdvancedPreview(ctx, s.AdvancedSearchTerms, opts...)
+		result.Response = &RegistrantAlertResponse{DomainsCount: count}
+	case s.BasicSearchTerms != nil:
+		result.Response, resp, err = api.BasicPurchase(ctx, s.BasicSearchTerms, opts...)
+	default:
+		result.Response, resp, err = api.AdvancedPurchase(ctx, s.AdvancedSearchTerms, opts...)
+	}
+
+	if err != nil {
+		result.Response = nil
+		return result, resp, err
+	}
+
+	return result, resp, nil
+}
+
+// LoadSavedSearches reads and validates the JSON array of saved searches from the file.
+func LoadSavedSearches(path string) (searches []SavedSearch, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open saved searches: %w", err)
+	}
+
+	defer func() {
+		if cerr := f.Close(); err == nil && cerr != nil {
+			err = fmt.Errorf("cannot close saved searches: %w", cerr)
+		}
+	}()
+
+	return ParseSavedSearches(f)
+}
+
+// ParseSavedSearches decodes and validates the JSON array of saved searches.
+// The names of the searches must be unique.
+func ParseSavedSearches(r io.Reader) ([]SavedSearch, error) {
+	var searches []SavedSearch
+
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&searches); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			return nil, &ArgError{"savedSearches." + typeErr.Field, "must be " + typeErr.Type.String() + "."}
+		}
+
+		return nil, fmt.Errorf("cannot parse saved searches: %w", err)
+	}
+
+	names := make(map[string]bool, len(searches))
+	for i, s := range searches {
+		prefix := "savedSearches." + strconv.Itoa(i) + "."
+
+		if err := s.validate(prefix); err != nil {
+			return nil, err
+		}
+
+		if names[s.Name] {
+			return nil, &ArgError{prefix + "name", "must be unique."}
+		}
+		names[s.Name] = true
+	}
+
+	return searches, nil
+}
@@ -0,0 +1,182 @@
+package registrantalert
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSavedSearchJSON tests the JSON round-tripping of the saved search.
+func TestSavedSearchJSON(t *testing.T) {
+	punycode := false
+	s := SavedSearch{
+		Search: Search{Name: "brand", BasicSearchTerms: &BasicSearchTerms{Include: []string{"whoisxmlapi"}}},
+		Options: RequestParams{
+			ResponseFormat: XML,
+			Punycode:       &punycode,
+			SinceDate:      "2022-10-01",
+		},
+		CreatedWithin: Duration(30 * 24 * time.Hour),
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"name":"brand","basicSearchTerms":{"include":["whoisxmlapi"]},` +
+		`"options":{"responseFormat":"xml","punycode":false,"sinceDate":"2022-10-01"},"createdWithin":"720h0m0s"}`
+	if string(b) != want {
+		t.Errorf("Marshal() = %s, want %s", b, want)
+	}
+
+	var got SavedSearch
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, s) {
+		t.Errorf("Unmarshal() = %+v, want %+v", got, s)
+	}
+}
+
+// TestSavedSearchValidate tests the validation of the saved search.
+func TestSavedSearchValidate(t *testing.T) {
+	basic := &BasicSearchTerms{Include: []string{"whois"}}
+	advanced := []AdvancedSearchTerm{{Field: "RegistrantContact.Organization", Term: "Airbnb, Inc."}}
+
+	tests := []struct {
+		name   string
+		search SavedSearch
+		want   string
+	}{
+		{"basic", SavedSearch{Search: Search{Name: "a", BasicSearchTerms: basic}}, ""},
+		{"advanced", SavedSearch{Search: Search{Name: "a", AdvancedSearchTerms: advanced}}, ""},
+		{"no name", SavedSearch{Search: Search{BasicSearchTerms: basic}},
+			`invalid argument: "savedSearch.name" is required.`},
+		{"no terms", SavedSearch{Search: Search{Name: "a"}},
+			`invalid argument: "savedSearch.searchTerms" must have basicSearchTerms or advancedSearchTerms.`},
+		{"both terms", SavedSearch{Search: Search{Name: "a", BasicSearchTerms: basic, AdvancedSearchTerms: advanced}},
+			`invalid argument: "savedSearch.searchTerms" must not have both basicSearchTerms and advancedSearchTerms.`},
+		{"invalid terms", SavedSearch{Search: Search{Name: "a", BasicSearchTerms: &BasicSearchTerms{}}},
+			`invalid argument: "savedSearch.basicSearchTerms.include" must have between 1 and 4 items.`},
+		{"invalid option", SavedSearch{Search: Search{Name: "a", BasicSearchTerms: basic}, Options: RequestParams{SinceDate: "10/01/2022"}},
+			`invalid argument: "savedSearch.options.sinceDate" must be a date in YYYY-MM-DD format.`},
+		{"relative dates", SavedSearch{Search: Search{Name: "a", BasicSearchTerms: basic},
+			SinceLast: Duration(7 * 24 * time.Hour), ExpiredWithin: Duration(-30 * 24 * time.Hour)}, ""},
+		{"negative since last", SavedSearch{Search: Search{Name: "a", BasicSearchTerms: basic}, SinceLast: Duration(-time.Hour)},
+			`invalid argument: "savedSearch.sinceLast" must not be negative.`},
+		{"relative and absolute dates", SavedSearch{Search: Search{Name: "a", BasicSearchTerms: basic},
+			Options: RequestParams{CreatedDateTo: "2022-10-01"}, CreatedWithin: Duration(time.Hour)},
+			`invalid argument: "savedSearch.createdWithin" must not be set with savedSearch.options.createdDateFrom or createdDateTo.`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkErr(t, tt.search.Validate(), tt.want)
+		})
+	}
+}
+
+// TestParseSavedSearches tests the decoding of the saved search list.
+func TestParseSavedSearches(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"valid", `[{"name":"a","basicSearchTerms":{"include":["whois"]}},` +
+			`{"name":"b","advancedSearchTerms":[{"field":"RegistrantContact.Email","term":"admin@example.com"}]}]`, ""},
+		{"unknown field", `[{"name":"a","mode":"preview"}]`, `cannot parse saved searches: json: unknown field "mode"`},
+		{"wrong type", `[{"name":1}]`, `invalid argument: "savedSearches.0.name" must be string.`},
+		{"invalid search", `[{"name":"a","basicSearchTerms":{"include":["whois"]}},{"name":"b"}]`,
+			`invalid argument: "savedSearches.1.searchTerms" must have basicSearchTerms or advancedSearchTerms.`},
+		{"duplicate name", `[{"name":"a","basicSearchTerms":{"include":["whois"]}},{"name":"a","basicSearchTerms":{"include":["xml"]}}]`,
+			`invalid argument: "savedSearches.1.name" must be unique.`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searches, err := ParseSavedSearches(strings.NewReader(tt.input))
+			checkErr(t, err, tt.want)
+			if tt.want == "" && len(searches) != 2 {
+				t.Errorf("ParseSavedSearches() = %+v", searches)
+			}
+		})
+	}
+}
+
+// TestSavedSearchRun tests the execution of the saved search in both modes.
+func TestSavedSearchRun(t *testing.T) {
+	t.Parallel()
+
+	var got wireRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		got = wireRequest{}
+		if err := json.NewDecoder(req.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+		_, _ = w.Write([]byte(`{"domainsCount":1,"domainsList":[{"domainName":"whoisxmlapi.net","action":"added","date":"2022-10-30"}]}`))
+	}))
+	defer server.Close()
+
+	apiURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient(apiKey, ClientParams{
+		HTTPClient:             server.Client(),
+		RegistrantAlertBaseURL: apiURL,
+		Calendar:               fixedCalendar(time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)),
+	})
+
+	s := SavedSearch{
+		Search:  Search{Name: "brand", AdvancedSearchTerms: []AdvancedSearchTerm{{Field: "DomainName", Term: "whoisxmlapi"}}},
+		Options: RequestParams{SinceDate: "2022-10-01", CreatedDateFrom: "2022-01-01"},
+	}
+
+	result, _, err := s.Run(context.Background(), client, RunPreview)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Mode != "preview" || got.SinceDate != "2022-10-01" || len(got.AdvancedSearchTerms) != 1 {
+		t.Errorf("preview request = %+v", got)
+	}
+	if result.Search.Name != "brand" || result.RunAt.IsZero() || result.Response.DomainsCount != 1 ||
+		len(result.Response.DomainsList) != 0 {
+		t.Errorf("preview result = %+v", result)
+	}
+
+	result, _, err = s.Run(context.Background(), client, RunPurchase,
+		OptionSinceDate(time.Date(2022, 10, 15, 0, 0, 0, 0, time.UTC)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Mode != "purchase" || got.SinceDate != "2022-10-15" || got.CreatedDateFrom != "2022-01-01" {
+		t.Errorf("purchase request = %+v", got)
+	}
+	if domainNames(result.Response)[0] != "whoisxmlapi.net" {
+		t.Errorf("purchase result = %+v", result.Response)
+	}
+
+	relative := SavedSearch{
+		Search:        s.Search,
+		SinceLast:     Duration(7 * 24 * time.Hour),
+		CreatedWithin: Duration(30 * 24 * time.Hour),
+	}
+	if _, _, err = relative.Run(context.Background(), client, RunPreview); err != nil {
+		t.Fatal(err)
+	}
+	if got.SinceDate != "2022-10-25" || got.CreatedDateFrom != "2022-10-02" || got.CreatedDateTo != "2022-11-01" {
+		t.Errorf("relative request = %+v", got)
+	}
+
+	_, _, err = s.Run(context.Background(), client, "dry-run")
+	checkErr(t, err, `invalid argument: "mode" must be preview or purchase.`)
+}
@@ -0,0 +1,95 @@
+package registrantalert
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Search describes the search the results originate from.
+// It is used by the exporters and notifiers to label the results.
+type Search struct {
+	// Name is the human-readable name of the search.
+	Name string `json:"name,omitempty"`
+
+	// BasicSearchTerms is the set of search terms for the Basic search.
+	BasicSearchTerms *BasicSearchTerms `json:"basicSearchTerms,omitempty"`
+
+	// AdvancedSearchTerms is the set of search terms for the Advanced search.
+	AdvancedSearchTerms []AdvancedSearchTerm `json:"advancedSearchTerms,omitempty"`
+}
+
+// SearchResult is the response of a search run labelled with the search and the run time.
+type SearchResult struct {
+	// Search is the search the response originates from.
+	Search Search `json:"search"`
+
+	// RunAt is the time of the search run.
+	RunAt time.Time `json:"runAt"`
+
+	// Response is the parsed Registrant Alert API response.
+	Response *RegistrantAlertResponse `json:"response"`
+}
+
+// domains returns the domains of the result or nil if there is no response.
+func (r SearchResult) domains() []DomainItem {
+	if r.Response == nil {
+		return nil
+	}
+	return r.Response.DomainsList
+}
+
+// Terms returns the human-readable description of the search terms, e.g.
+// `include: "Airbnb", "US"; exclude: "EU"` or `RegistrantContact.Organization = "Airbnb, Inc."`.
+func (s Search) Terms() string {
+	var parts []string
+
+	if s.BasicSearchTerms != nil {
+		if len(s.BasicSearchTerms.Include) > 0 {
+			parts = append(parts, "include: "+quoteList(s.BasicSearchTerms.Include))
+		}
+		if len(s.BasicSearchTerms.Exclude) > 0 {
+			parts = append(parts, "exclude: "+quoteList(s.BasicSearchTerms.Exclude))
+		}
+	}
+
+	for _, term := range s.AdvancedSearchTerms {
+		op := " contains "
+		if term.ExactMatch {
+			op = " = "
+		}
+		parts = append(parts, term.Field+op+strconv.Quote(term.Term))
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// String returns the name of the search or the description of its terms if the name is empty.
+func (s Search) String() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.Terms()
+}
+
+// key returns the stable representation of the search terms. The name is not included.
+func (s Search) key() string {
+	b, err := json.Marshal(Search{
+		BasicSearchTerms:    s.BasicSearchTerms,
+		AdvancedSearchTerms: s.AdvancedSearchTerms,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
+// quoteList returns the comma-separated list of quoted strings.
+func quoteList(list []string) string {
+	quoted := make([]string, len(list))
+	for i, s := range list {
+		quoted[i] = strconv.Quote(s)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,40 @@
+package registrantalert
+
+import (
+	"testing"
+)
+
+// TestSearch tests the Search description functions.
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name   string
+		search Search
+		want   string
+	}{
+		{
+			name:   "named",
+			search: Search{Name: "Airbnb", BasicSearchTerms: &BasicSearchTerms{Include: []string{"Airbnb"}}},
+			want:   "Airbnb",
+		},
+		{
+			name:   "basic",
+			search: Search{BasicSearchTerms: &BasicSearchTerms{Include: []string{"Airbnb", "US"}, Exclude: []string{"EU"}}},
+			want:   `include: "Airbnb", "US"; exclude: "EU"`,
+		},
+		{
+			name: "advanced",
+			search: Search{AdvancedSearchTerms: []AdvancedSearchTerm{
+				{"RegistrantContact.Organization", "Airbnb, Inc.", true},
+				{"RegistrantContact.Country", "US", false},
+			}},
+			want: `RegistrantContact.Organization = "Airbnb, Inc."; RegistrantContact.Country contains "US"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.search.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
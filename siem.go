@@ -0,0 +1,384 @@
+package registrantalert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultHECSourceType is the Splunk sourcetype of the events when none is specified.
+const defaultHECSourceType = "registrant_alert"
+
+// DocumentID returns the stable identifier of the domain event derived from the domain name,
+// the action and the date. Writing the same event twice produces the same document.
+func DocumentID(item DomainItem) string {
+	var date string
+	if !item.Date.IsZero() {
+		date = time.Time(item.Date).Format(dateFormat)
+	}
+
+	name := strings.ToLower(item.DomainName) + "|" + string(item.Action) + "|" + date
+
+	return newUUIDv5(libraryNamespace, "event|"+name).String()
+}
+
+// siemEvent is the document of the domain event.
+// The date is omitted if it's empty, as the empty string fails the date mappings.
+// The timestamp is set in the bulk documents only, the HEC events have their own time.
+type siemEvent struct {
+	Timestamp  *Time  `json:"@timestamp,omitempty"`
+	DomainName string `json:"domainName"`
+	Action     Action `json:"action"`
+	Date       *Time  `json:"date,omitempty"`
+	Search     string `json:"search,omitempty"`
+}
+
+// newSIEMEvent returns the document of the domain event.
+func newSIEMEvent(item DomainItem, search string) siemEvent {
+	event := siemEvent{DomainName: item.DomainName, Action: item.Action, Search: search}
+	if !item.Date.IsZero() {
+		date := item.Date
+		event.Date = &date
+	}
+	return event
+}
+
+// BulkOptions configures the Elasticsearch bulk output.
+type BulkOptions struct {
+	// Index is the target index or data stream. It is required.
+	Index string
+
+	// Create writes the create actions instead of index ones. Data streams accept only create actions.
+	// Documents that already exist are skipped by Elasticsearch and are not reported as failures by Send.
+	Create bool
+
+	// Search is added to the documents as the search field.
+	Search Search
+
+	// Time is the time of the search run. It is the @timestamp of the undated records.
+	// If it's zero then the current time is used.
+	Time time.Time
+}
+
+// bulkTarget is the target document of the bulk action.
+type bulkTarget struct {
+	Index string `json:"_index"`
+	ID    string `json:"_id"`
+}
+
+// WriteBulk writes the records in the Elasticsearch/OpenSearch _bulk NDJSON format.
+// Every record is indexed with DocumentID, so repeated writes do not duplicate documents.
+// The actions are index or, if BulkOptions.Create is set, create ones.
+// The @timestamp of the documents, required by data streams, is the record date or the run time.
+func WriteBulk(w io.Writer, items []DomainItem, opts BulkOptions) error {
+	if opts.Index == "" {
+		return &ArgError{"BulkOptions.Index", "is required."}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	search := opts.Search.String()
+
+	op := "index"
+	if opts.Create {
+		op = "create"
+	}
+
+	runTime := opts.Time
+	if runTime.IsZero() {
+		runTime = time.Now()
+	}
+	runTimestamp := Time(runTime.UTC())
+
+	for _, item := range items {
+		action := map[string]bulkTarget{op: {opts.Index, DocumentID(item)}}
+
+		event := newSIEMEvent(item, search)
+		event.Timestamp = event.Date
+		if event.Timestamp == nil {
+			event.Timestamp = &runTimestamp
+		}
+
+		if err := enc.Encode(action); err != nil {
+			return fmt.Errorf("cannot write bulk: %w", err)
+		}
+		if err := enc.Encode(event); err != nil {
+			return fmt.Errorf("cannot write bulk: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// HECOptions configures the Splunk HTTP Event Collector output.
+type HECOptions struct {
+	// Index is the Splunk index. If it's empty then the default index of the token is used.
+	Index string
+
+	// SourceType is the event sourcetype. Default: "registrant_alert".
+	SourceType string
+
+	// Source is the event source.
+	Source string
+
+	// Host is the event host.
+	Host string
+
+	// Search is added to the events as the search field.
+	Search Search
+}
+
+// hecEvent is the Splunk HTTP Event Collector event.
+type hecEvent struct {
+	Time       *int64            `json:"time,omitempty"`
+	Host       string            `json:"host,omitempty"`
+	Source     string            `json:"source,omitempty"`
+	SourceType string            `json:"sourcetype"`
+	Index      string            `json:"index,omitempty"`
+	Event      siemEvent         `json:"event"`
+	Fields     map[string]string `json:"fields"`
+}
+
+// WriteHEC writes the records as Splunk HTTP Event Collector JSON events.
+// The event time is the record date, the DocumentID is added as the indexed field "eventId".
+func WriteHEC(w io.Writer, items []DomainItem, opts HECOptions) error {
+	sourceType := opts.SourceType
+	if sourceType == "" {
+		sourceType = defaultHECSourceType
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	search := opts.Search.String()
+
+	for _, item := range items {
+		event := hecEvent{
+			Host:       opts.Host,
+			Source:     opts.Source,
+			SourceType: sourceType,
+			Index:      opts.Index,
+			Event:      newSIEMEvent(item, search),
+			Fields:     map[string]string{"eventId": DocumentID(item)},
+		}
+		if !item.Date.IsZero() {
+			sec := time.Time(item.Date).Unix()
+			event.Time = &sec
+		}
+
+		if err := enc.Encode(event); err != nil {
+			return fmt.Errorf("cannot write hec: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ElasticsearchParams is used to create ElasticsearchSender. Only URL is mandatory.
+type ElasticsearchParams struct {
+	// URL is the base URL of the cluster, e.g. "https://localhost:9200".
+	URL string
+
+	// Username and Password are the basic authentication credentials.
+	Username string
+	Password string
+
+	// APIKey is the base64-encoded API key. It takes precedence over the basic authentication.
+	APIKey string
+
+	// HTTPClient is the client used to access the cluster.
+	// If it's nil then http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// ElasticsearchSender indexes the records with the Elasticsearch/OpenSearch _bulk API.
+type ElasticsearchSender struct {
+	params ElasticsearchParams
+	client *http.Client
+}
+
+// NewElasticsearchSender creates ElasticsearchSender with specified parameters.
+func NewElasticsearchSender(params ElasticsearchParams) (*ElasticsearchSender, error) {
+	if _, ok := parseHTTPURL(params.URL); !ok {
+		return nil, &ArgError{"ElasticsearchParams.URL", "must be an absolute http(s) URL."}
+	}
+
+	client := http.DefaultClient
+	if params.HTTPClient != nil {
+		client = params.HTTPClient
+	}
+
+	return &ElasticsearchSender{params: params, client: client}, nil
+}
+
+// bulkResponse is the part of the _bulk API response used to detect the failed items.
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// Send indexes the records. The failure of any record is reported as DeliveryError.
+func (s *ElasticsearchSender) Send(ctx context.Context, items []DomainItem, opts BulkOptions) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	if err := WriteBulk(&body, items, opts); err != nil {
+		return err
+	}
+
+	endpoint := strings.TrimSuffix(s.params.URL, "/") + "/_bulk"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, &body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	req.Header.Set("User-Agent", userAgent)
+	if s.params.APIKey != "" {
+		req.Header.Set("Authorization", "ApiKey "+s.params.APIKey)
+	} else if s.params.Username != "" {
+		req.SetBasicAuth(s.params.Username, s.params.Password)
+	}
+
+	respBody, statusCode, err := doSIEMRequest(s.client, req)
+	if err != nil || statusCode < 200 || statusCode > 299 {
+		return &DeliveryError{URL: endpoint, StatusCode: statusCode, Err: err}
+	}
+
+	var resp bulkResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return &DeliveryError{URL: endpoint, StatusCode: statusCode, Err: fmt.Errorf("cannot parse response: %w", err)}
+	}
+	if !resp.Errors {
+		return nil
+	}
+
+	failed := 0
+	var reason string
+	for _, item := range resp.Items {
+		for _, result := range item {
+			if result.Error == nil || opts.Create && result.Status == http.StatusConflict {
+				continue
+			}
+			failed++
+			if reason == "" {
+				reason = result.Error.Type + ": " + result.Error.Reason
+			}
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+
+	return &DeliveryError{
+		URL:        endpoint,
+		StatusCode: statusCode,
+		Err:        fmt.Errorf("%d of %d documents failed, first error: %s", failed, len(items), reason),
+	}
+}
+
+// SplunkParams is used to create SplunkSender. URL and Token are mandatory.
+type SplunkParams struct {
+	// URL is the base URL of the HTTP Event Collector, e.g. "https://localhost:8088".
+	URL string
+
+	// Token is the HTTP Event Collector token.
+	Token string
+
+	// HTTPClient is the client used to access the collector.
+	// If it's nil then http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// SplunkSender posts the records to the Splunk HTTP Event Collector.
+type SplunkSender struct {
+	params SplunkParams
+	client *http.Client
+}
+
+// NewSplunkSender creates SplunkSender with specified parameters.
+func NewSplunkSender(params SplunkParams) (*SplunkSender, error) {
+	if _, ok := parseHTTPURL(params.URL); !ok {
+		return nil, &ArgError{"SplunkParams.URL", "must be an absolute http(s) URL."}
+	}
+	if params.Token == "" {
+		return nil, &ArgError{"SplunkParams.Token", "is required."}
+	}
+
+	client := http.DefaultClient
+	if params.HTTPClient != nil {
+		client = params.HTTPClient
+	}
+
+	return &SplunkSender{params: params, client: client}, nil
+}
+
+// Send posts the records to the /services/collector/event endpoint.
+func (s *SplunkSender) Send(ctx context.Context, items []DomainItem, opts HECOptions) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	if err := WriteHEC(&body, items, opts); err != nil {
+		return err
+	}
+
+	endpoint := strings.TrimSuffix(s.params.URL, "/") + "/services/collector/event"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, &body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", mediaType)
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Authorization", "Splunk "+s.params.Token)
+
+	respBody, statusCode, err := doSIEMRequest(s.client, req)
+	if err == nil && (statusCode < 200 || statusCode > 299) {
+		var resp struct {
+			Text string `json:"text"`
+			Code int    `json:"code"`
+		}
+		if json.Unmarshal(respBody, &resp) == nil && resp.Text != "" {
+			err = fmt.Errorf("[%d] %s", resp.Code, resp.Text)
+		}
+	}
+	if err != nil || statusCode < 200 || statusCode > 299 {
+		return &DeliveryError{URL: endpoint, StatusCode: statusCode, Err: err}
+	}
+
+	return nil
+}
+
+// doSIEMRequest sends the request and returns the response body and status code.
+func doSIEMRequest(client *http.Client, req *http.Request) ([]byte, int, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("cannot read response: %w", err)
+	}
+
+	return body, resp.StatusCode, nil
+}
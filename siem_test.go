@@ -0,0 +1,198 @@
+package registrantalert
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDocumentID tests the stability of the document identifiers.
+func TestDocumentID(t *testing.T) {
+	items := testDomainItems()
+
+	if DocumentID(items[0]) != DocumentID(items[0]) {
+		t.Error("document id is not stable")
+	}
+
+	upper := items[0]
+	upper.DomainName = "BatchWhois.com"
+	if DocumentID(upper) != DocumentID(items[0]) {
+		t.Error("document id depends on the domain name case")
+	}
+
+	other := items[0]
+	other.Action = Dropped
+	if DocumentID(other) == DocumentID(items[0]) {
+		t.Error("document id does not depend on the action")
+	}
+}
+
+// TestWriteBulk tests the Elasticsearch bulk format.
+func TestWriteBulk(t *testing.T) {
+	items := testDomainItems()
+
+	var b bytes.Buffer
+	err := WriteBulk(&b, items[:1], BulkOptions{Index: "registrant-alert", Search: Search{Name: "brand"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"index":{"_index":"registrant-alert","_id":"` + DocumentID(items[0]) + `"}}` + "\n" +
+		`{"@timestamp":"2022-10-30","domainName":"batchwhois.com","action":"discovered","date":"2022-10-30","search":"brand"}` + "\n"
+	if b.String() != want {
+		t.Errorf("WriteBulk() = %s, want %s", b.String(), want)
+	}
+
+	b.Reset()
+	if err := WriteBulk(&b, items[:1], BulkOptions{Index: "logs-registrant-alert", Create: true}); err != nil {
+		t.Fatal(err)
+	}
+	want = `{"create":{"_index":"logs-registrant-alert","_id":"` + DocumentID(items[0]) + `"}}` + "\n" +
+		`{"@timestamp":"2022-10-30","domainName":"batchwhois.com","action":"discovered","date":"2022-10-30"}` + "\n"
+	if b.String() != want {
+		t.Errorf("WriteBulk() = %s, want %s", b.String(), want)
+	}
+
+	b.Reset()
+	runTime := time.Date(2022, 11, 1, 10, 30, 0, 0, time.UTC)
+	if err := WriteBulk(&b, items[2:3], BulkOptions{Index: "logs-registrant-alert", Create: true, Time: runTime}); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"@timestamp":"2022-11-01T10:30:00Z","domainName":"whoisdodster.com","action":"added"}` + "\n"; !strings.HasSuffix(b.String(), want) {
+		t.Errorf("WriteBulk() = %s, want suffix %s", b.String(), want)
+	}
+
+	checkErr(t, WriteBulk(&b, items, BulkOptions{}), `invalid argument: "BulkOptions.Index" is required.`)
+}
+
+// TestWriteHEC tests the Splunk HTTP Event Collector format.
+func TestWriteHEC(t *testing.T) {
+	items := testDomainItems()
+
+	var b bytes.Buffer
+	if err := WriteHEC(&b, []DomainItem{items[0], items[2]}, HECOptions{Index: "security"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"time":1667088000,"sourcetype":"registrant_alert","index":"security",` +
+		`"event":{"domainName":"batchwhois.com","action":"discovered","date":"2022-10-30"},` +
+		`"fields":{"eventId":"` + DocumentID(items[0]) + `"}}` + "\n" +
+		`{"sourcetype":"registrant_alert","index":"security",` +
+		`"event":{"domainName":"whoisdodster.com","action":"added"},` +
+		`"fields":{"eventId":"` + DocumentID(items[2]) + `"}}` + "\n"
+	if b.String() != want {
+		t.Errorf("WriteHEC() = %s, want %s", b.String(), want)
+	}
+}
+
+// TestElasticsearchSender tests the bulk request and the item failures.
+func TestElasticsearchSender(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ = io.ReadAll(req.Body)
+
+		if req.URL.Path != "/_bulk" || req.Header.Get("Authorization") != "ApiKey key" ||
+			req.Header.Get("Content-Type") != "application/x-ndjson" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"errors":true,"items":[{"index":{"status":201}},` +
+			`{"index":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse"}}}]}`))
+	}))
+	defer server.Close()
+
+	sender, err := NewElasticsearchSender(ElasticsearchParams{URL: server.URL + "/", APIKey: "key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := testDomainItems()[:2]
+	err = sender.Send(context.Background(), items, BulkOptions{Index: "registrant-alert"})
+
+	var deliveryErr *DeliveryError
+	if !errors.As(err, &deliveryErr) || deliveryErr.StatusCode != http.StatusOK {
+		t.Fatalf("error = %v", err)
+	}
+	checkErr(t, err, "cannot deliver to "+server.URL+"/_bulk: 1 of 2 documents failed, "+
+		"first error: mapper_parsing_exception: failed to parse")
+
+	var want bytes.Buffer
+	_ = WriteBulk(&want, items, BulkOptions{Index: "registrant-alert"})
+	if string(body) != want.String() {
+		t.Errorf("body = %s, want %s", body, want.String())
+	}
+
+	_, err = NewElasticsearchSender(ElasticsearchParams{URL: "localhost:9200"})
+	checkErr(t, err, `invalid argument: "ElasticsearchParams.URL" must be an absolute http(s) URL.`)
+}
+
+// TestElasticsearchSenderCreate tests that existing documents are not failures of the create actions.
+func TestElasticsearchSenderCreate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(`{"errors":true,"items":[{"create":{"status":201}},` +
+			`{"create":{"status":409,"error":{"type":"version_conflict_engine_exception","reason":"document already exists"}}}]}`))
+	}))
+	defer server.Close()
+
+	sender, err := NewElasticsearchSender(ElasticsearchParams{URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := testDomainItems()[:2]
+	if err := sender.Send(context.Background(), items, BulkOptions{Index: "logs-registrant-alert", Create: true}); err != nil {
+		t.Errorf("Send() error = %v", err)
+	}
+
+	err = sender.Send(context.Background(), items, BulkOptions{Index: "registrant-alert"})
+	checkErr(t, err, "cannot deliver to "+server.URL+"/_bulk: 1 of 2 documents failed, "+
+		"first error: version_conflict_engine_exception: document already exists")
+}
+
+// TestSplunkSender tests the HTTP Event Collector request and its errors.
+func TestSplunkSender(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/services/collector/event" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if req.Header.Get("Authorization") != "Splunk token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"text":"Invalid token","code":4}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"text":"Success","code":0}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name  string
+		token string
+		want  string
+	}{
+		{"success", "token", ""},
+		{"invalid token", "other", "cannot deliver to " + server.URL + "/services/collector/event: [4] Invalid token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender, err := NewSplunkSender(SplunkParams{URL: server.URL, Token: tt.token})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = sender.Send(context.Background(), testDomainItems(), HECOptions{})
+			checkErr(t, err, tt.want)
+		})
+	}
+
+	_, err := NewSplunkSender(SplunkParams{URL: server.URL})
+	checkErr(t, err, `invalid argument: "SplunkParams.Token" is required.`)
+}
@@ -0,0 +1,229 @@
+package registrantalert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// stixObservableNamespace is the namespace of STIX 2.1 Cyber-observable Object IDs.
+var stixObservableNamespace = mustParseUUID("00abedb4-aa42-466c-9c01-fed23315a9b7")
+
+// stixTimestampFormat is the STIX 2.1 timestamp format with millisecond precision.
+const stixTimestampFormat = "2006-01-02T15:04:05.000Z"
+
+// stixUndatedTimestamp is the timestamp of the domains without the date when none is specified.
+// It's fixed, as the objects of such domains keep their IDs across exports and STIX 2.1
+// does not allow the created time of an object to change.
+var stixUndatedTimestamp = time.Unix(0, 0).UTC()
+
+// STIXOptions configures the STIX 2.1 export.
+type STIXOptions struct {
+	// Search is the search the response originates from.
+	// Its terms are included in the indicator descriptions and make the IDs unique per search.
+	Search Search
+
+	// CreatedByRef is the optional ID of the identity object of the producer.
+	CreatedByRef string
+
+	// Timestamp is used instead of the domain's date when the date is empty.
+	// It should not change between exports, as the IDs of such domains do not depend on it.
+	// If it's zero then the Unix epoch is used.
+	Timestamp time.Time
+}
+
+// STIXBundle is the STIX 2.1 bundle.
+type STIXBundle struct {
+	Type    string        `json:"type"`
+	ID      string        `json:"id"`
+	Objects []interface{} `json:"objects"`
+}
+
+// STIXDomainName is the STIX 2.1 domain-name observable.
+type STIXDomainName struct {
+	Type        string `json:"type"`
+	SpecVersion string `json:"spec_version"`
+	ID          string `json:"id"`
+	Value       string `json:"value"`
+}
+
+// STIXIndicator is the STIX 2.1 indicator.
+type STIXIndicator struct {
+	Type           string   `json:"type"`
+	SpecVersion    string   `json:"spec_version"`
+	ID             string   `json:"id"`
+	CreatedByRef   string   `json:"created_by_ref,omitempty"`
+	Created        string   `json:"created"`
+	Modified       string   `json:"modified"`
+	Name           string   `json:"name"`
+	Description    string   `json:"description,omitempty"`
+	IndicatorTypes []string `json:"indicator_types"`
+	Pattern        string   `json:"pattern"`
+	PatternType    string   `json:"pattern_type"`
+	ValidFrom      string   `json:"valid_from"`
+	Labels         []string `json:"labels"`
+}
+
+// STIXRelationship is the STIX 2.1 relationship.
+type STIXRelationship struct {
+	Type             string `json:"type"`
+	SpecVersion      string `json:"spec_version"`
+	ID               string `json:"id"`
+	CreatedByRef     string `json:"created_by_ref,omitempty"`
+	Created          string `json:"created"`
+	Modified         string `json:"modified"`
+	RelationshipType string `json:"relationship_type"`
+	SourceRef        string `json:"source_ref"`
+	TargetRef        string `json:"target_ref"`
+}
+
+// stixDomainNameID returns the ID of the domain-name observable as defined by STIX 2.1:
+// UUIDv5 of the canonical JSON of the ID contributing properties.
+func stixDomainNameID(domainName string) string {
+	var b bytes.Buffer
+
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(struct {
+		Value string `json:"value"`
+	}{domainName}); err != nil {
+		panic(err)
+	}
+
+	return "domain-name--" + newUUIDv5(stixObservableNamespace, strings.TrimSuffix(b.String(), "\n")).String()
+}
+
+// stixPatternString escapes the string literal of the STIX pattern.
+func stixPatternString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// NewSTIXBundle converts the response to the STIX 2.1 bundle.
+// Every domain becomes a domain-name observable, an indicator labelled with the action
+// and the relationship between them. The IDs are derived from the search terms and the domain's
+// name, action and date, so exporting the same results again produces the same objects.
+// Domains without the date are identified by the name and action only and get the fixed
+// fallback timestamp, so their objects do not change between exports.
+func NewSTIXBundle(resp *RegistrantAlertResponse, opts STIXOptions) (*STIXBundle, error) {
+	if resp == nil {
+		return nil, &ArgError{"resp", "is required."}
+	}
+
+	fallback := opts.Timestamp
+	if fallback.IsZero() {
+		fallback = stixUndatedTimestamp
+	}
+
+	searchKey := opts.Search.key()
+	terms := opts.Search.Terms()
+
+	objects := []interface{}{}
+	var ids []string
+	seen := make(map[string]bool)
+
+	for _, item := range resp.DomainsList {
+		if item.DomainName == "" {
+			continue
+		}
+
+		date := time.Time(item.Date)
+		if item.Date.IsZero() {
+			date = fallback
+		}
+		timestamp := date.UTC().Format(stixTimestampFormat)
+
+		observableID := stixDomainNameID(item.DomainName)
+		if !seen[observableID] {
+			seen[observableID] = true
+			ids = append(ids, observableID)
+			objects = append(objects, &STIXDomainName{
+				Type:        "domain-name",
+				SpecVersion: "2.1",
+				ID:          observableID,
+				Value:       item.DomainName,
+			})
+		}
+
+		name := searchKey + "|" + item.DomainName + "|" + string(item.Action)
+		if !item.Date.IsZero() {
+			name += "|" + timestamp
+		}
+		indicatorID := "indicator--" + newUUIDv5(libraryNamespace, "indicator|"+name).String()
+		if seen[indicatorID] {
+			continue
+		}
+		seen[indicatorID] = true
+
+		description := fmt.Sprintf("Domain %s was %s according to Registrant Alert API.", item.DomainName, item.Action)
+		if terms != "" {
+			description += " Search terms: " + terms + "."
+		}
+
+		labels := []string{"registrant-alert"}
+		if item.Action != "" {
+			labels = append(labels, string(item.Action))
+		}
+
+		relationshipID := "relationship--" + newUUIDv5(libraryNamespace, "relationship|"+name).String()
+
+		ids = append(ids, indicatorID, relationshipID)
+		objects = append(objects,
+			&STIXIndicator{
+				Type:           "indicator",
+				SpecVersion:    "2.1",
+				ID:             indicatorID,
+				CreatedByRef:   opts.CreatedByRef,
+				Created:        timestamp,
+				Modified:       timestamp,
+				Name:           fmt.Sprintf("Registrant Alert: %s %s", item.DomainName, item.Action),
+				Description:    description,
+				IndicatorTypes: []string{"unknown"},
+				Pattern:        "[domain-name:value = " + stixPatternString(item.DomainName) + "]",
+				PatternType:    "stix",
+				ValidFrom:      timestamp,
+				Labels:         labels,
+			},
+			&STIXRelationship{
+				Type:             "relationship",
+				SpecVersion:      "2.1",
+				ID:               relationshipID,
+				CreatedByRef:     opts.CreatedByRef,
+				Created:          timestamp,
+				Modified:         timestamp,
+				RelationshipType: "based-on",
+				SourceRef:        indicatorID,
+				TargetRef:        observableID,
+			},
+		)
+	}
+
+	sort.Strings(ids)
+
+	return &STIXBundle{
+		Type:    "bundle",
+		ID:      "bundle--" + newUUIDv5(libraryNamespace, "bundle|"+strings.Join(ids, ",")).String(),
+		Objects: objects,
+	}, nil
+}
+
+// WriteSTIX writes the response as the STIX 2.1 bundle in JSON.
+func WriteSTIX(w io.Writer, resp *RegistrantAlertResponse, opts STIXOptions) error {
+	bundle, err := NewSTIXBundle(resp, opts)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(bundle); err != nil {
+		return fmt.Errorf("cannot write stix: %w", err)
+	}
+
+	return nil
+}
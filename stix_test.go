@@ -0,0 +1,111 @@
+package registrantalert
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestUUIDv5 tests the name-based UUID generation.
+func TestUUIDv5(t *testing.T) {
+	const want = "886313e1-3b8a-5372-9b90-0c9aee199e5d"
+
+	if got := newUUIDv5(dnsNamespace, "python.org").String(); got != want {
+		t.Errorf("newUUIDv5() = %v, want %v", got, want)
+	}
+
+	if got := mustParseUUID(want).String(); got != want {
+		t.Errorf("mustParseUUID() = %v, want %v", got, want)
+	}
+}
+
+// TestNewSTIXBundle tests the STIX 2.1 export.
+func TestNewSTIXBundle(t *testing.T) {
+	resp := &RegistrantAlertResponse{
+		DomainsList: []DomainItem{
+			{"batchwhois.com", Added, Time(time.Date(2022, 10, 30, 0, 0, 0, 0, time.UTC))},
+			{"batchwhois.com", Updated, Time(time.Date(2022, 10, 31, 0, 0, 0, 0, time.UTC))},
+			{"who'is.com", Discovered, emptyTime},
+		},
+		DomainsCount: 3,
+	}
+	opts := STIXOptions{
+		Search:    Search{Name: "whois", BasicSearchTerms: &BasicSearchTerms{Include: []string{"whois"}}},
+		Timestamp: time.Date(2022, 11, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	bundle, err := NewSTIXBundle(resp, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2 observables, 3 indicators and 3 relationships.
+	if len(bundle.Objects) != 8 {
+		t.Fatalf("got %d objects, want 8", len(bundle.Objects))
+	}
+
+	observable := bundle.Objects[0].(*STIXDomainName)
+	if !strings.HasPrefix(observable.ID, "domain-name--") || observable.ID != stixDomainNameID("batchwhois.com") {
+		t.Errorf("observable ID = %v", observable.ID)
+	}
+
+	indicator := bundle.Objects[1].(*STIXIndicator)
+	if indicator.ValidFrom != "2022-10-30T00:00:00.000Z" || indicator.Labels[1] != "added" ||
+		indicator.Pattern != "[domain-name:value = 'batchwhois.com']" ||
+		indicator.Description != `Domain batchwhois.com was added according to Registrant Alert API. Search terms: include: "whois".` {
+		t.Errorf("indicator = %+v", indicator)
+	}
+
+	last := bundle.Objects[6].(*STIXIndicator)
+	if last.ValidFrom != "2022-11-01T12:00:00.000Z" || last.Pattern != `[domain-name:value = 'who\'is.com']` {
+		t.Errorf("indicator = %+v", last)
+	}
+
+	var first, second bytes.Buffer
+	if err := WriteSTIX(&first, resp, opts); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteSTIX(&second, resp, opts); err != nil {
+		t.Fatal(err)
+	}
+	if first.String() != second.String() {
+		t.Error("WriteSTIX() output is not deterministic")
+	}
+
+	var decoded STIXBundle
+	if err := json.Unmarshal(first.Bytes(), &decoded); err != nil || decoded.ID != bundle.ID {
+		t.Errorf("WriteSTIX() wrote bundle %v, err %v", decoded.ID, err)
+	}
+
+	opts.Timestamp = opts.Timestamp.Add(time.Hour)
+	later, _ := NewSTIXBundle(resp, opts)
+	if later.Objects[6].(*STIXIndicator).ID != last.ID || later.Objects[7].(*STIXRelationship).ID != bundle.Objects[7].(*STIXRelationship).ID {
+		t.Error("IDs of the domain without the date depend on the fallback timestamp")
+	}
+
+	opts.Timestamp = time.Time{}
+	undated, _ := NewSTIXBundle(resp, opts)
+	if got := undated.Objects[6].(*STIXIndicator).Created; got != "1970-01-01T00:00:00.000Z" {
+		t.Errorf("created time of the domain without the date = %v, want the Unix epoch", got)
+	}
+
+	opts.Search.BasicSearchTerms = &BasicSearchTerms{Include: []string{"lookup"}}
+	other, _ := NewSTIXBundle(resp, opts)
+	if other.Objects[1].(*STIXIndicator).ID == indicator.ID {
+		t.Error("indicator IDs of different searches are equal")
+	}
+}
+
+// TestNewSTIXBundleEmpty tests the bundle of the empty response.
+func TestNewSTIXBundleEmpty(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteSTIX(&b, &RegistrantAlertResponse{}, STIXOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(b.String(), `"objects": []`) {
+		t.Errorf("WriteSTIX() = %s, want empty objects", b.String())
+	}
+}
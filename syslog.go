@@ -0,0 +1,369 @@
+package registrantalert
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// syslogSDID is the structured data ID of the Registrant Alert events.
+// 32473 is the private enterprise number reserved for documentation by RFC 5612.
+const syslogSDID = "registrantAlert@32473"
+
+// defaultSyslogAppName is the APP-NAME of the syslog messages when none is specified.
+const defaultSyslogAppName = "registrant-alert"
+
+// SyslogFacility is the syslog facility as defined by RFC 5424.
+type SyslogFacility int
+
+// List of commonly used syslog facilities.
+const (
+	SyslogKern     SyslogFacility = 0
+	SyslogUser     SyslogFacility = 1
+	SyslogDaemon   SyslogFacility = 3
+	SyslogAuth     SyslogFacility = 4
+	SyslogAuthPriv SyslogFacility = 10
+	SyslogAudit    SyslogFacility = 13
+	SyslogLocal0   SyslogFacility = 16
+)
+
+var _ = []SyslogFacility{
+	SyslogKern,
+	SyslogUser,
+	SyslogDaemon,
+	SyslogAuth,
+	SyslogAuthPriv,
+	SyslogAudit,
+	SyslogLocal0,
+}
+
+// SyslogSeverity is the syslog severity as defined by RFC 5424.
+type SyslogSeverity int
+
+// List of syslog severities.
+const (
+	SyslogEmergency SyslogSeverity = iota
+	SyslogAlert
+	SyslogCritical
+	SyslogError
+	SyslogWarning
+	SyslogNotice
+	SyslogInfo
+	SyslogDebug
+)
+
+var _ = []SyslogSeverity{
+	SyslogEmergency,
+	SyslogAlert,
+	SyslogCritical,
+	SyslogError,
+	SyslogWarning,
+	SyslogNotice,
+	SyslogInfo,
+	SyslogDebug,
+}
+
+// SyslogParams is used to create SyslogEmitter. Only Addr is mandatory.
+type SyslogParams struct {
+	// Network is "udp", "tcp", "unix" or "unixgram". Default: "udp".
+	// Messages sent over TCP are framed with octet counting (RFC 6587),
+	// messages sent over unix stream sockets are terminated with a newline as local syslog daemons expect.
+	Network string
+
+	// Addr is the address of the syslog server, e.g. "siem.example.com:514" or "/dev/log".
+	Addr string
+
+	// Facility is the syslog facility. Default: SyslogUser.
+	Facility *SyslogFacility
+
+	// Severity is the syslog severity. Default: SyslogNotice.
+	Severity *SyslogSeverity
+
+	// Hostname is the HOSTNAME of the messages. If it's empty then the local host name is used.
+	Hostname string
+
+	// AppName is the APP-NAME of the messages. Default: "registrant-alert".
+	AppName string
+
+	// CEF enables the ArcSight Common Event Format message body.
+	CEF bool
+
+	// Timeout is the connection and write timeout. Default: 10 seconds.
+	Timeout time.Duration
+}
+
+// SyslogEmitter writes Registrant Alert events as RFC 5424 syslog messages.
+type SyslogEmitter struct {
+	params   SyslogParams
+	facility SyslogFacility
+	severity SyslogSeverity
+	hostname string
+	procID   string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSyslogEmitter creates SyslogEmitter with specified parameters and connects to the server.
+func NewSyslogEmitter(params SyslogParams) (*SyslogEmitter, error) {
+	if params.Network == "" {
+		params.Network = "udp"
+	}
+	switch params.Network {
+	case "udp", "tcp", "unix", "unixgram":
+	default:
+		return nil, &ArgError{"SyslogParams.Network", `must be one of "udp", "tcp", "unix", "unixgram".`}
+	}
+
+	if params.Addr == "" {
+		return nil, &ArgError{"SyslogParams.Addr", "is required."}
+	}
+
+	facility := SyslogUser
+	if params.Facility != nil {
+		facility = *params.Facility
+	}
+	if facility < SyslogKern || facility > 23 {
+		return nil, &ArgError{"SyslogParams.Facility", "must be between 0 and 23."}
+	}
+
+	severity := SyslogNotice
+	if params.Severity != nil {
+		severity = *params.Severity
+	}
+	if severity < SyslogEmergency || severity > SyslogDebug {
+		return nil, &ArgError{"SyslogParams.Severity", "must be between 0 and 7."}
+	}
+
+	if params.AppName == "" {
+		params.AppName = defaultSyslogAppName
+	}
+	if params.Timeout == 0 {
+		params.Timeout = 10 * time.Second
+	}
+
+	hostname := params.Hostname
+	if hostname == "" {
+		hostname, _ = os.Hostname()
+	}
+
+	e := &SyslogEmitter{
+		params:   params,
+		facility: facility,
+		severity: severity,
+		hostname: hostname,
+		procID:   strconv.Itoa(os.Getpid()),
+	}
+
+	if err := e.connect(); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// connect dials the syslog server.
+func (e *SyslogEmitter) connect() error {
+	conn, err := net.DialTimeout(e.params.Network, e.params.Addr, e.params.Timeout)
+	if err != nil {
+		return fmt.Errorf("cannot connect to syslog: %w", err)
+	}
+
+	e.conn = conn
+	return nil
+}
+
+// Emit writes every domain as a separate syslog message.
+func (e *SyslogEmitter) Emit(items ...DomainItem) error {
+	return e.emit(Search{}, items)
+}
+
+// EmitResults writes the domains of every search result, tagged with the search name.
+func (e *SyslogEmitter) EmitResults(results ...SearchResult) error {
+	for _, result := range results {
+		if err := e.emit(result.Search, result.domains()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// emit writes the domains of the search.
+func (e *SyslogEmitter) emit(search Search, items []DomainItem) error {
+	name := search.String()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, item := range items {
+		if err := e.write(e.format(time.Now(), name, item)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// write sends the message, reconnecting once if the stream connection is broken.
+func (e *SyslogEmitter) write(msg []byte) error {
+	switch e.params.Network {
+	case "tcp":
+		msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+	case "unix":
+		msg = append(msg[:len(msg):len(msg)], '\n')
+	}
+	stream := e.params.Network == "tcp" || e.params.Network == "unix"
+
+	for attempt := 0; ; attempt++ {
+		if e.conn == nil {
+			if err := e.connect(); err != nil {
+				return err
+			}
+		}
+
+		_ = e.conn.SetWriteDeadline(time.Now().Add(e.params.Timeout))
+		_, err := e.conn.Write(msg)
+		if err == nil {
+			return nil
+		}
+
+		_ = e.conn.Close()
+		e.conn = nil
+
+		if !stream || attempt > 0 {
+			return fmt.Errorf("cannot write to syslog: %w", err)
+		}
+	}
+}
+
+// Close closes the connection to the syslog server.
+func (e *SyslogEmitter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn == nil {
+		return nil
+	}
+
+	err := e.conn.Close()
+	e.conn = nil
+
+	return err
+}
+
+// format returns the RFC 5424 message of the domain.
+func (e *SyslogEmitter) format(now time.Time, search string, item DomainItem) []byte {
+	var b bytes.Buffer
+
+	pri := int(e.facility)*8 + int(e.severity)
+	b.WriteString("<" + strconv.Itoa(pri) + ">1 ")
+	b.WriteString(now.UTC().Format("2006-01-02T15:04:05.000000Z07:00") + " ")
+	b.WriteString(syslogHeaderField(e.hostname, 255) + " ")
+	b.WriteString(syslogHeaderField(e.params.AppName, 48) + " ")
+	b.WriteString(syslogHeaderField(e.procID, 128) + " ")
+	b.WriteString(syslogHeaderField(string(item.Action), 32) + " ")
+
+	var date string
+	if !item.Date.IsZero() {
+		date = time.Time(item.Date).Format(dateFormat)
+	}
+
+	b.WriteString("[" + syslogSDID)
+	writeSDParam(&b, "domainName", item.DomainName)
+	writeSDParam(&b, "action", string(item.Action))
+	if date != "" {
+		writeSDParam(&b, "date", date)
+	}
+	if search != "" {
+		writeSDParam(&b, "search", search)
+	}
+	b.WriteString("] ")
+
+	if e.params.CEF {
+		b.WriteString(cefMessage(search, item))
+	} else {
+		b.WriteString("domain " + item.DomainName + " " + string(item.Action))
+		if date != "" {
+			b.WriteString(" on " + date)
+		}
+	}
+
+	return b.Bytes()
+}
+
+// syslogHeaderField returns the header field limited to printable US-ASCII, or the nil value "-".
+func syslogHeaderField(s string, maxLen int) string {
+	if s == "" {
+		return "-"
+	}
+
+	b := []byte(s)
+	if len(b) > maxLen {
+		b = b[:maxLen]
+	}
+	for i, c := range b {
+		if c < 33 || c > 126 {
+			b[i] = '_'
+		}
+	}
+
+	return string(b)
+}
+
+// sdParamEscape escapes the characters of the structured data parameter values.
+var sdParamEscape = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "]", `\]`)
+
+// writeSDParam writes the structured data parameter.
+func writeSDParam(b *bytes.Buffer, name, value string) {
+	b.WriteString(" " + name + `="` + sdParamEscape.Replace(value) + `"`)
+}
+
+// cefSeverity is the CEF severity of the actions.
+var cefSeverity = map[Action]int{
+	Added:      7,
+	Discovered: 7,
+	Updated:    5,
+	Dropped:    3,
+}
+
+// cefHeaderEscape escapes the characters of the CEF header fields.
+var cefHeaderEscape = strings.NewReplacer(`\`, `\\`, "|", `\|`, "\r", " ", "\n", " ")
+
+// cefValueEscape escapes the characters of the CEF extension values.
+var cefValueEscape = strings.NewReplacer(`\`, `\\`, "=", `\=`, "\r", `\r`, "\n", `\n`)
+
+// cefMessage returns the ArcSight Common Event Format message of the domain.
+func cefMessage(search string, item DomainItem) string {
+	severity, ok := cefSeverity[item.Action]
+	if !ok {
+		severity = 5
+	}
+
+	header := []string{
+		"CEF:0",
+		"WhoisXML API",
+		"Registrant Alert",
+		libraryVersion,
+		cefHeaderEscape.Replace(string(item.Action)),
+		cefHeaderEscape.Replace("Domain " + string(item.Action)),
+		strconv.Itoa(severity),
+	}
+
+	ext := []string{
+		"dhost=" + cefValueEscape.Replace(item.DomainName),
+		"act=" + cefValueEscape.Replace(string(item.Action)),
+	}
+	if !item.Date.IsZero() {
+		ext = append(ext, "rt="+strconv.FormatInt(time.Time(item.Date).UnixNano()/int64(time.Millisecond), 10))
+	}
+	if search != "" {
+		ext = append(ext, "cs1Label=search", "cs1="+cefValueEscape.Replace(search))
+	}
+
+	return strings.Join(header, "|") + "|" + strings.Join(ext, " ")
+}
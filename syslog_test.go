@@ -0,0 +1,219 @@
+package registrantalert
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSyslogEmitterUDP tests the RFC 5424 messages sent over UDP.
+func TestSyslogEmitterUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	facility, severity := SyslogAudit, SyslogWarning
+	emitter, err := NewSyslogEmitter(SyslogParams{
+		Addr:     conn.LocalAddr().String(),
+		Facility: &facility,
+		Severity: &severity,
+		Hostname: "collector 1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer emitter.Close()
+
+	items := testDomainItems()
+	err = emitter.EmitResults(SearchResult{
+		Search:   Search{Name: `brand "x"`},
+		Response: &RegistrantAlertResponse{DomainsList: items[:1]},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 2048)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := regexp.MustCompile(`^<108>1 \d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{6}Z collector_1 registrant-alert \d+ ` +
+		regexp.QuoteMeta(string(items[0].Action)+` [registrantAlert@32473 domainName="`+items[0].DomainName+
+			`" action="`+string(items[0].Action)+`" date="`+time.Time(items[0].Date).Format(dateFormat)+
+			`" search="brand \"x\""] domain `+items[0].DomainName+" "+string(items[0].Action)+
+			" on "+time.Time(items[0].Date).Format(dateFormat)) + `$`)
+	if msg := string(buf[:n]); !want.MatchString(msg) {
+		t.Errorf("message = %s", msg)
+	}
+}
+
+// TestSyslogEmitterTCP tests the octet-counted CEF messages sent over TCP.
+func TestSyslogEmitterTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 10)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		for {
+			length, err := r.ReadString(' ')
+			if err != nil {
+				close(received)
+				return
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(length))
+			msg := make([]byte, n)
+			if _, err := io.ReadFull(r, msg); err != nil {
+				close(received)
+				return
+			}
+			received <- string(msg)
+		}
+	}()
+
+	emitter, err := NewSyslogEmitter(SyslogParams{Network: "tcp", Addr: ln.Addr().String(), CEF: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := testDomainItems()
+	items[0].DomainName = "a=b|c.com"
+	if err := emitter.Emit(items...); err != nil {
+		t.Fatal(err)
+	}
+	if err := emitter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var messages []string
+	for msg := range received {
+		messages = append(messages, msg)
+	}
+
+	if len(messages) != len(items) {
+		t.Fatalf("received %d messages, want %d", len(messages), len(items))
+	}
+
+	wantCEF := "CEF:0|WhoisXML API|Registrant Alert|" + libraryVersion + "|" + string(items[0].Action) +
+		"|Domain " + string(items[0].Action) + "|7|dhost=a\\=b|c.com act=" + string(items[0].Action) +
+		" rt=" + strconv.FormatInt(time.Time(items[0].Date).UnixNano()/int64(time.Millisecond), 10)
+	if !strings.HasPrefix(messages[0], "<13>1 ") || !strings.HasSuffix(messages[0], "] "+wantCEF) {
+		t.Errorf("message = %s, want CEF %s", messages[0], wantCEF)
+	}
+}
+
+// TestSyslogEmitterUnix tests the newline-terminated messages sent over the unix stream socket.
+func TestSyslogEmitterUnix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 10)
+	go func() {
+		defer close(received)
+
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		for {
+			msg, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			received <- msg
+		}
+	}()
+
+	emitter, err := NewSyslogEmitter(SyslogParams{Network: "unix", Addr: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := testDomainItems()
+	if err := emitter.Emit(items...); err != nil {
+		t.Fatal(err)
+	}
+	if err := emitter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var messages []string
+	for msg := range received {
+		messages = append(messages, msg)
+	}
+
+	if len(messages) != len(items) {
+		t.Fatalf("received %d messages, want %d", len(messages), len(items))
+	}
+	if !strings.HasPrefix(messages[0], "<13>1 ") {
+		t.Errorf("message = %q, want no octet count", messages[0])
+	}
+}
+
+// TestNewSyslogEmitter tests the parameter validation.
+func TestNewSyslogEmitter(t *testing.T) {
+	facility, severity := SyslogFacility(24), SyslogSeverity(8)
+
+	tests := []struct {
+		name   string
+		params SyslogParams
+		want   string
+	}{
+		{"network", SyslogParams{Network: "http", Addr: "localhost:514"},
+			`invalid argument: "SyslogParams.Network" must be one of "udp", "tcp", "unix", "unixgram".`},
+		{"addr", SyslogParams{},
+			`invalid argument: "SyslogParams.Addr" is required.`},
+		{"facility", SyslogParams{Addr: "localhost:514", Facility: &facility},
+			`invalid argument: "SyslogParams.Facility" must be between 0 and 23.`},
+		{"severity", SyslogParams{Addr: "localhost:514", Severity: &severity},
+			`invalid argument: "SyslogParams.Severity" must be between 0 and 7.`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSyslogEmitter(tt.params)
+			checkErr(t, err, tt.want)
+		})
+	}
+}
+
+// TestSyslogFacilityKern tests that the kernel facility, which is zero, can be chosen.
+func TestSyslogFacilityKern(t *testing.T) {
+	kern := SyslogKern
+	emitter, err := NewSyslogEmitter(SyslogParams{Addr: "127.0.0.1:514", Facility: &kern})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer emitter.Close()
+
+	if emitter.facility != SyslogKern {
+		t.Errorf("facility = %d, want %d", emitter.facility, SyslogKern)
+	}
+}
@@ -0,0 +1,185 @@
+package registrantalert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultRetryBackoff is the initial delay between retries when none is specified.
+const defaultRetryBackoff = 500 * time.Millisecond
+
+// maxRetryBackoff caps the delay between retries.
+const maxRetryBackoff = 30 * time.Second
+
+// backoffDelay returns the delay before the given retry attempt (starting from 1).
+// The delay doubles on every attempt and never exceeds maxRetryBackoff.
+func backoffDelay(base time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		base = defaultRetryBackoff
+	}
+
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= maxRetryBackoff {
+			return maxRetryBackoff
+		}
+	}
+
+	return delay
+}
+
+// sleepContext waits for the given duration or until the context is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryableStatus reports whether the request may succeed if it is sent again.
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// retryTransport is the http.RoundTripper resending failed requests.
+type retryTransport struct {
+	next       http.RoundTripper
+	maxRetries int
+	backoff    time.Duration
+}
+
+// RoundTrip executes the request and retries it on network errors, 429 and 5xx status codes.
+// Purchase requests deduct credits, so they are retried only on 429 and on connection errors
+// that happened before the request was sent.
+// Requests with a body which cannot be replayed are sent only once.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil && req.GetBody == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	purchase := isPurchaseRequest(req)
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			if err := sleepContext(req.Context(), backoffDelay(t.backoff, attempt)); err != nil {
+				return nil, err
+			}
+
+			if req.Body != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+
+				req = req.Clone(req.Context())
+				req.Body = body
+			}
+		}
+
+		resp, err := t.next.RoundTrip(req)
+		if attempt >= t.maxRetries || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		if err != nil {
+			if purchase && !notSent(err) {
+				return resp, err
+			}
+			continue
+		}
+
+		if !retryableStatus(resp.StatusCode) || purchase && resp.StatusCode != http.StatusTooManyRequests {
+			return resp, nil
+		}
+
+		resp.Body.Close()
+	}
+}
+
+// isPurchaseRequest reports whether the body of the request is the API call in the purchase mode.
+func isPurchaseRequest(req *http.Request) bool {
+	if req.GetBody == nil {
+		return false
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return false
+	}
+	defer body.Close()
+
+	var payload struct {
+		Mode string `json:"mode"`
+	}
+	if err := json.NewDecoder(io.LimitReader(body, 1<<20)).Decode(&payload); err != nil {
+		return false
+	}
+
+	return payload.Mode == "purchase"
+}
+
+// notSent reports whether the error happened before the request was sent, e.g. on dialing.
+func notSent(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// rateLimitTransport is the http.RoundTripper spacing requests out evenly.
+type rateLimitTransport struct {
+	next     http.RoundTripper
+	interval time.Duration
+
+	mu       sync.Mutex
+	nextSlot time.Time
+}
+
+// maxRateLimit is the highest rate limit, the interval between the requests is 1ns at it.
+const maxRateLimit = float64(time.Second)
+
+// newRateLimitTransport creates the transport allowing up to requestsPerSecond requests per second.
+// The rate must be positive and not above maxRateLimit, see Config.Validate.
+func newRateLimitTransport(next http.RoundTripper, requestsPerSecond float64) *rateLimitTransport {
+	return &rateLimitTransport{
+		next:     next,
+		interval: time.Duration(float64(time.Second) / requestsPerSecond),
+	}
+}
+
+// RoundTrip waits for the next free slot and executes the request.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	now := time.Now()
+	slot := t.nextSlot
+	if slot.Before(now) {
+		slot = now
+	}
+	t.nextSlot = slot.Add(t.interval)
+	t.mu.Unlock()
+
+	if err := sleepContext(req.Context(), slot.Sub(now)); err != nil {
+		return nil, err
+	}
+
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,374 @@
+package registrantalert
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TyposquatReason is the kind of similarity between the domain and the brand.
+type TyposquatReason string
+
+// List of typosquat reasons.
+const (
+	ReasonExact        TyposquatReason = "exact"
+	ReasonTLDSwap      TyposquatReason = "tld-swap"
+	ReasonHomoglyph    TyposquatReason = "homoglyph"
+	ReasonHyphen       TyposquatReason = "hyphen"
+	ReasonKeyboard     TyposquatReason = "keyboard"
+	ReasonEditDistance TyposquatReason = "edit-distance"
+	ReasonKeyword      TyposquatReason = "keyword"
+	ReasonSubdomain    TyposquatReason = "subdomain"
+	ReasonContains     TyposquatReason = "contains"
+)
+
+var _ = []TyposquatReason{
+	ReasonExact,
+	ReasonTLDSwap,
+	ReasonHomoglyph,
+	ReasonHyphen,
+	ReasonKeyboard,
+	ReasonEditDistance,
+	ReasonKeyword,
+	ReasonSubdomain,
+	ReasonContains,
+}
+
+// typosquatScores are the scores of the reasons.
+var typosquatScores = map[TyposquatReason]float64{
+	ReasonExact:        1.0,
+	ReasonTLDSwap:      0.95,
+	ReasonHomoglyph:    0.95,
+	ReasonHyphen:       0.9,
+	ReasonKeyboard:     0.85,
+	ReasonEditDistance: 0.8,
+	ReasonKeyword:      0.8,
+	ReasonSubdomain:    0.7,
+	ReasonContains:     0.6,
+}
+
+// defaultTyposquatKeywords are the words commonly added to the brand names in phishing domains.
+var defaultTyposquatKeywords = []string{
+	"account", "app", "auth", "billing", "help", "login", "mail", "my", "official", "online",
+	"pay", "portal", "secure", "security", "shop", "signin", "store", "support", "update", "verify", "www",
+}
+
+// asciiHomoglyphs are the ASCII sequences commonly used to imitate letters.
+var asciiHomoglyphs = strings.NewReplacer("0", "o", "1", "l", "3", "e", "5", "s", "rn", "m", "vv", "w")
+
+// keyboardRows is the QWERTY layout used to detect the adjacent keys.
+var keyboardRows = []string{"1234567890-", "qwertyuiop", "asdfghjkl", "zxcvbnm"}
+
+// TyposquatParams is used to create TyposquatAnalyzer. Only Brands are mandatory.
+type TyposquatParams struct {
+	// Brands are the protected brand names, e.g. "whoisxmlapi", or their domains, e.g. "whoisxmlapi.com".
+	// The domains of the brands are never reported, other public suffixes are reported as TLD swaps.
+	Brands []string
+
+	// Keywords are the words combined with the brand names in phishing domains, e.g. "login".
+	// If it's nil then the default list is used.
+	Keywords []string
+
+	// MinScore is the minimum score of the reported matches, from 0 to 1.
+	MinScore float64
+}
+
+// TyposquatMatch is the domain resembling the brand.
+type TyposquatMatch struct {
+	// Item is the matching domain.
+	Item DomainItem
+
+	// Brand is the protected brand name as configured.
+	Brand string
+
+	// Score is the similarity from 0 to 1, where 1 is the brand name itself.
+	Score float64
+
+	// Reason is the kind of similarity.
+	Reason TyposquatReason
+
+	// Detail is the human-readable explanation, e.g. `adjacent keys "q" and "w"`.
+	Detail string
+}
+
+// typosquatBrand is the normalized brand.
+type typosquatBrand struct {
+	name     string
+	label    string
+	skeleton string
+	domain   string
+	suffix   string
+}
+
+// TyposquatAnalyzer scores the domains against the protected brand names.
+type TyposquatAnalyzer struct {
+	brands   []typosquatBrand
+	keywords map[string]bool
+	minScore float64
+}
+
+// NewTyposquatAnalyzer creates TyposquatAnalyzer with specified parameters.
+func NewTyposquatAnalyzer(params TyposquatParams) (*TyposquatAnalyzer, error) {
+	if len(params.Brands) == 0 {
+		return nil, &ArgError{"TyposquatParams.Brands", "must have at least 1 item."}
+	}
+	if params.MinScore < 0 || params.MinScore > 1 {
+		return nil, &ArgError{"TyposquatParams.MinScore", "must be between 0 and 1."}
+	}
+
+	a := &TyposquatAnalyzer{keywords: make(map[string]bool), minScore: params.MinScore}
+
+	for i, name := range params.Brands {
+		info, err := ParseDomainName(name)
+		if err != nil {
+			return nil, &ArgError{"TyposquatParams.Brands." + strconv.Itoa(i), "must be a valid name or domain."}
+		}
+
+		brand := typosquatBrand{name: name, label: info.Unicode}
+		if info.Labels > 1 {
+			if info.RegistrableDomain == "" {
+				return nil, &ArgError{"TyposquatParams.Brands." + strconv.Itoa(i), "must not be a public suffix."}
+			}
+			rdInfo, _ := ParseDomainName(info.RegistrableDomain)
+			brand.label = strings.SplitN(rdInfo.Unicode, ".", 2)[0]
+			brand.domain = info.RegistrableDomain
+			brand.suffix = info.PublicSuffix
+		}
+		brand.skeleton = typosquatSkeleton(brand.label)
+
+		a.brands = append(a.brands, brand)
+	}
+
+	keywords := params.Keywords
+	if keywords == nil {
+		keywords = defaultTyposquatKeywords
+	}
+	for _, keyword := range keywords {
+		a.keywords[strings.ToLower(keyword)] = true
+	}
+
+	return a, nil
+}
+
+// Score returns the matches of the domain against every brand, the best first.
+func (a *TyposquatAnalyzer) Score(item DomainItem) []TyposquatMatch {
+	info, err := item.Info()
+	if err != nil || info.RegistrableDomain == "" {
+		return nil
+	}
+
+	unicode, _ := ToUnicode(info.RegistrableDomain)
+	label := strings.SplitN(unicode, ".", 2)[0]
+
+	var subdomains []string
+	if n := info.Labels - strings.Count(info.RegistrableDomain, ".") - 1; n > 0 {
+		subdomains = strings.Split(info.Unicode, ".")[:n]
+	}
+
+	var matches []TyposquatMatch
+	for _, brand := range a.brands {
+		if brand.domain != "" && brand.domain == info.RegistrableDomain {
+			continue
+		}
+
+		reason, detail := a.compare(brand, label, info.PublicSuffix, subdomains)
+		if reason == "" || typosquatScores[reason] < a.minScore {
+			continue
+		}
+
+		matches = append(matches, TyposquatMatch{
+			Item:   item,
+			Brand:  brand.name,
+			Score:  typosquatScores[reason],
+			Reason: reason,
+			Detail: detail,
+		})
+	}
+
+	sortTyposquatMatches(matches)
+
+	return matches
+}
+
+// Analyze returns the matches of all domains ranked by the score.
+func (a *TyposquatAnalyzer) Analyze(items []DomainItem) []TyposquatMatch {
+	var matches []TyposquatMatch
+	for _, item := range items {
+		matches = append(matches, a.Score(item)...)
+	}
+
+	sortTyposquatMatches(matches)
+
+	return matches
+}
+
+// sortTyposquatMatches sorts the matches by the score descending, then by the domain name.
+func sortTyposquatMatches(matches []TyposquatMatch) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Item.DomainName < matches[j].Item.DomainName
+	})
+}
+
+// compare returns the strongest reason of the similarity between the label and the brand.
+func (a *TyposquatAnalyzer) compare(brand typosquatBrand, label, suffix string, subdomains []string) (TyposquatReason, string) {
+	if label == brand.label {
+		if brand.suffix != "" && suffix != brand.suffix {
+			return ReasonTLDSwap, "public suffix " + suffix + " instead of " + brand.suffix
+		}
+		return ReasonExact, "brand name with public suffix " + suffix
+	}
+
+	if typosquatSkeleton(label) == brand.skeleton {
+		return ReasonHomoglyph, "look-alike characters of " + strconv.Quote(brand.label)
+	}
+
+	if strings.ReplaceAll(label, "-", "") == brand.label {
+		return ReasonHyphen, "hyphens added to " + strconv.Quote(brand.label)
+	}
+
+	if typed, intended, ok := adjacentKeySubstitution(label, brand.label); ok {
+		return ReasonKeyboard, "adjacent keys " + strconv.Quote(string(typed)) + " and " + strconv.Quote(string(intended))
+	}
+
+	if n := len([]rune(brand.label)); n >= 5 {
+		maxDistance := 1
+		if n >= 8 {
+			maxDistance = 2
+		}
+		if d := editDistance(label, brand.label); d <= maxDistance {
+			return ReasonEditDistance, "edit distance " + strconv.Itoa(d) + " from " + strconv.Quote(brand.label)
+		}
+	}
+
+	if strings.Contains(label, brand.label) {
+		rest := strings.Trim(strings.Replace(label, brand.label, "", 1), "-")
+		if a.keywords[rest] {
+			return ReasonKeyword, "keyword " + strconv.Quote(rest) + " added to " + strconv.Quote(brand.label)
+		}
+	}
+
+	for _, subdomain := range subdomains {
+		if subdomain == brand.label {
+			return ReasonSubdomain, "brand name used as a subdomain"
+		}
+	}
+
+	if strings.Contains(label, brand.label) {
+		return ReasonContains, "contains " + strconv.Quote(brand.label)
+	}
+
+	return "", ""
+}
+
+// typosquatSkeleton replaces the look-alike characters with the letters they imitate.
+func typosquatSkeleton(label string) string {
+	label = strings.Map(func(r rune) rune {
+		if latin, ok := confusables[r]; ok {
+			return latin
+		}
+		return r
+	}, label)
+
+	return asciiHomoglyphs.Replace(label)
+}
+
+// keyPosition returns the row and the doubled horizontal position of the key.
+// The rows are staggered by half a key.
+func keyPosition(r rune) (int, int, bool) {
+	for row, keys := range keyboardRows {
+		if col := strings.IndexRune(keys, r); col >= 0 {
+			return row, 2*col + row, true
+		}
+	}
+	return 0, 0, false
+}
+
+// adjacentKeys reports whether the keys are neighbours on the keyboard.
+func adjacentKeys(a, b rune) bool {
+	rowA, xA, okA := keyPosition(a)
+	rowB, xB, okB := keyPosition(b)
+	if !okA || !okB {
+		return false
+	}
+
+	dx := xA - xB
+	if dx < 0 {
+		dx = -dx
+	}
+
+	switch rowA - rowB {
+	case 0:
+		return dx == 2
+	case 1, -1:
+		return dx <= 1
+	}
+	return false
+}
+
+// adjacentKeySubstitution returns the substituted characters if the strings differ
+// only by one character typed with the neighbouring key.
+func adjacentKeySubstitution(s, t string) (rune, rune, bool) {
+	rs, rt := []rune(s), []rune(t)
+	if len(rs) != len(rt) {
+		return 0, 0, false
+	}
+
+	diff := -1
+	for i := range rs {
+		if rs[i] != rt[i] {
+			if diff >= 0 {
+				return 0, 0, false
+			}
+			diff = i
+		}
+	}
+
+	if diff < 0 || !adjacentKeys(rs[diff], rt[diff]) {
+		return 0, 0, false
+	}
+
+	return rs[diff], rt[diff], true
+}
+
+// editDistance returns the Damerau-Levenshtein distance (optimal string alignment) between the strings.
+func editDistance(s, t string) int {
+	rs, rt := []rune(s), []rune(t)
+
+	d := make([][]int, len(rs)+1)
+	for i := range d {
+		d[i] = make([]int, len(rt)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(rs); i++ {
+		for j := 1; j <= len(rt); j++ {
+			cost := 1
+			if rs[i-1] == rt[j-1] {
+				cost = 0
+			}
+
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && rs[i-1] == rt[j-2] && rs[i-2] == rt[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(rs)][len(rt)]
+}
+
+// minInt returns the smallest of the numbers.
+func minInt(first int, rest ...int) int {
+	for _, v := range rest {
+		if v < first {
+			first = v
+		}
+	}
+	return first
+}
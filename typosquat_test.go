@@ -0,0 +1,108 @@
+package registrantalert
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestTyposquatAnalyzer tests the scoring of the domains against the brands.
+func TestTyposquatAnalyzer(t *testing.T) {
+	a, err := NewTyposquatAnalyzer(TyposquatParams{Brands: []string{"whoisxmlapi.com", "paypal"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		domain     string
+		wantBrand  string
+		wantReason TyposquatReason
+		wantDetail string
+	}{
+		{"whoisxmlapi.com", "", "", ""},
+		{"www.whoisxmlapi.com", "", "", ""},
+		{"paypal.net", "paypal", ReasonExact, "brand name with public suffix net"},
+		{"whoisxmlapi.co.uk", "whoisxmlapi.com", ReasonTLDSwap, "public suffix co.uk instead of com"},
+		{"xn--pypal-4ve.com", "paypal", ReasonHomoglyph, `look-alike characters of "paypal"`},
+		{"paypa1.com", "paypal", ReasonHomoglyph, `look-alike characters of "paypal"`},
+		{"whois-xml-api.com", "whoisxmlapi.com", ReasonHyphen, `hyphens added to "whoisxmlapi"`},
+		{"psypal.com", "paypal", ReasonKeyboard, `adjacent keys "s" and "a"`},
+		{"paypla.com", "paypal", ReasonEditDistance, `edit distance 1 from "paypal"`},
+		{"whoisxmapi.org", "whoisxmlapi.com", ReasonEditDistance, `edit distance 1 from "whoisxmlapi"`},
+		{"paypal-login.com", "paypal", ReasonKeyword, `keyword "login" added to "paypal"`},
+		{"securepaypal.com", "paypal", ReasonKeyword, `keyword "secure" added to "paypal"`},
+		{"paypal.evil.com", "paypal", ReasonSubdomain, "brand name used as a subdomain"},
+		{"mypaypalrewards.com", "paypal", ReasonContains, `contains "paypal"`},
+		{"example.com", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			matches := a.Score(DomainItem{DomainName: tt.domain, Action: Added})
+
+			if tt.wantReason == "" {
+				if len(matches) != 0 {
+					t.Errorf("Score() = %+v, want no matches", matches)
+				}
+				return
+			}
+
+			if len(matches) != 1 {
+				t.Fatalf("Score() = %+v, want 1 match", matches)
+			}
+			m := matches[0]
+			if m.Brand != tt.wantBrand || m.Reason != tt.wantReason || m.Detail != tt.wantDetail ||
+				m.Score != typosquatScores[tt.wantReason] {
+				t.Errorf("Score() = %+v, want %s %s %s", m, tt.wantBrand, tt.wantReason, tt.wantDetail)
+			}
+		})
+	}
+}
+
+// TestTyposquatAnalyze tests the ranking and the score threshold.
+func TestTyposquatAnalyze(t *testing.T) {
+	a, err := NewTyposquatAnalyzer(TyposquatParams{Brands: []string{"paypal"}, MinScore: 0.8})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := []DomainItem{
+		{DomainName: "mypaypalrewards.com"},
+		{DomainName: "paypla.com"},
+		{DomainName: "paypal.net"},
+		{DomainName: "paypa1.com"},
+	}
+
+	var got []string
+	for _, m := range a.Analyze(items) {
+		got = append(got, m.Item.DomainName)
+	}
+
+	want := "paypal.net paypa1.com paypla.com"
+	if joined := strings.Join(got, " "); joined != want {
+		t.Errorf("Analyze() = %s, want %s", joined, want)
+	}
+}
+
+// TestNewTyposquatAnalyzer tests the parameter validation.
+func TestNewTyposquatAnalyzer(t *testing.T) {
+	tests := []struct {
+		name   string
+		params TyposquatParams
+		want   string
+	}{
+		{"no brands", TyposquatParams{}, `invalid argument: "TyposquatParams.Brands" must have at least 1 item.`},
+		{"invalid brand", TyposquatParams{Brands: []string{"ok", "-bad"}},
+			`invalid argument: "TyposquatParams.Brands.1" must be a valid name or domain.`},
+		{"public suffix", TyposquatParams{Brands: []string{"co.uk"}},
+			`invalid argument: "TyposquatParams.Brands.0" must not be a public suffix.`},
+		{"min score", TyposquatParams{Brands: []string{"ok"}, MinScore: 2},
+			`invalid argument: "TyposquatParams.MinScore" must be between 0 and 1.`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTyposquatAnalyzer(tt.params)
+			checkErr(t, err, tt.want)
+		})
+	}
+}
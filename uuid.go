@@ -0,0 +1,60 @@
+package registrantalert
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+)
+
+// uuid is the RFC 4122 UUID.
+type uuid [16]byte
+
+// dnsNamespace is the RFC 4122 namespace for fully-qualified domain names.
+var dnsNamespace = mustParseUUID("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+
+// libraryNamespace is the namespace of the deterministic IDs generated by this library.
+var libraryNamespace = newUUIDv5(dnsNamespace, "registrant-alert.whoisxmlapi.com")
+
+// mustParseUUID parses the UUID in the canonical form and panics on malformed input.
+func mustParseUUID(s string) uuid {
+	var u uuid
+
+	b, err := hex.DecodeString(s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:])
+	if err != nil || len(b) != len(u) {
+		panic("invalid UUID: " + s)
+	}
+	copy(u[:], b)
+
+	return u
+}
+
+// newUUIDv5 returns the name-based UUID (version 5) of the name in the namespace.
+func newUUIDv5(namespace uuid, name string) uuid {
+	var u uuid
+
+	h := sha1.New()
+	h.Write(namespace[:])
+	h.Write([]byte(name))
+	copy(u[:], h.Sum(nil))
+
+	u[6] = (u[6] & 0x0f) | 0x50
+	u[8] = (u[8] & 0x3f) | 0x80
+
+	return u
+}
+
+// String returns the UUID in the canonical form.
+func (u uuid) String() string {
+	var b [36]byte
+
+	hex.Encode(b[0:8], u[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], u[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], u[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], u[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:], u[10:])
+
+	return string(b[:])
+}
@@ -0,0 +1,289 @@
+package registrantalert
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// List of headers set by WebhookNotifier.
+const (
+	// WebhookSignatureHeader is the HMAC-SHA256 signature of the timestamp and the body: "sha256=<hex>".
+	WebhookSignatureHeader = "X-Registrant-Alert-Signature"
+
+	// WebhookTimestampHeader is the Unix time the request was signed at.
+	WebhookTimestampHeader = "X-Registrant-Alert-Timestamp"
+)
+
+// WebhookParams is used to create WebhookNotifier. Only URLs are mandatory.
+type WebhookParams struct {
+	// URLs are the webhook endpoints. Every payload is sent to all of them.
+	URLs []string
+
+	// Secret is the HMAC-SHA256 key of the payload signature.
+	// If it's empty then the requests are not signed.
+	Secret []byte
+
+	// Header is added to every request, e.g. for authorization.
+	Header http.Header
+
+	// HTTPClient is the client used to access the endpoints.
+	// If it's nil then http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// MaxRetries is the number of times a failed delivery is repeated. Zero disables retries.
+	MaxRetries int
+
+	// Backoff is the delay before the first retry. It doubles on every next attempt. Default: 500ms.
+	Backoff time.Duration
+
+	// DeadLetterPath is the file the undeliverable payloads are appended to as newline-delimited JSON.
+	// If it's empty then the undeliverable payloads are dropped.
+	DeadLetterPath string
+}
+
+// WebhookNotifier posts Registrant Alert events as JSON to webhook endpoints.
+type WebhookNotifier struct {
+	params WebhookParams
+	client *http.Client
+
+	mu sync.Mutex
+}
+
+// WebhookPayload is the JSON document posted by WebhookNotifier.Notify.
+// Every payload holds the events of a single search.
+type WebhookPayload struct {
+	// Search is the search the events originate from.
+	Search Search `json:"search"`
+
+	// SearchName is the name of the search or the description of its terms.
+	SearchName string `json:"searchName"`
+
+	// RunAt is the time of the search run.
+	RunAt time.Time `json:"runAt"`
+
+	// Events are the domains matching the search.
+	Events []DomainItem `json:"events"`
+}
+
+// DeliveryError is returned when the payload cannot be delivered to the endpoint.
+type DeliveryError struct {
+	URL        string
+	StatusCode int
+	Err        error
+}
+
+// Error returns error message as a string.
+func (e *DeliveryError) Error() string {
+	if e.Err != nil {
+		return "cannot deliver to " + e.URL + ": " + e.Err.Error()
+	}
+	return "cannot deliver to " + e.URL + ": status code " + strconv.Itoa(e.StatusCode)
+}
+
+// Unwrap returns the underlying error.
+func (e *DeliveryError) Unwrap() error {
+	return e.Err
+}
+
+// deadLetter is the record of the dead-letter file.
+type deadLetter struct {
+	URL      string          `json:"url"`
+	Error    string          `json:"error"`
+	FailedAt time.Time       `json:"failedAt"`
+	Payload  json.RawMessage `json:"payload"`
+}
+
+// NewWebhookNotifier creates WebhookNotifier with specified parameters.
+func NewWebhookNotifier(params WebhookParams) (*WebhookNotifier, error) {
+	if len(params.URLs) == 0 {
+		return nil, &ArgError{"WebhookParams.URLs", "must have at least 1 item."}
+	}
+
+	for i, rawURL := range params.URLs {
+		if _, ok := parseHTTPURL(rawURL); !ok {
+			return nil, &ArgError{"WebhookParams.URLs." + strconv.Itoa(i), "must be an absolute http(s) URL."}
+		}
+	}
+
+	if params.MaxRetries < 0 {
+		return nil, &ArgError{"WebhookParams.MaxRetries", "must not be negative."}
+	}
+
+	client := http.DefaultClient
+	if params.HTTPClient != nil {
+		client = params.HTTPClient
+	}
+
+	return &WebhookNotifier{params: params, client: client}, nil
+}
+
+// Notify posts the events of every search result as a separate payload.
+// Results without events are skipped. All payloads are attempted, the first error is returned.
+func (n *WebhookNotifier) Notify(ctx context.Context, results ...SearchResult) error {
+	var firstErr error
+
+	for _, result := range results {
+		events := result.domains()
+		if len(events) == 0 {
+			continue
+		}
+
+		body, err := json.Marshal(WebhookPayload{
+			Search:     result.Search,
+			SearchName: result.Search.String(),
+			RunAt:      result.RunAt,
+			Events:     events,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := n.Post(ctx, body); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// Post sends the JSON body to all endpoints, retrying failed deliveries.
+// Undeliverable payloads are written to the dead-letter file. The first error is returned.
+func (n *WebhookNotifier) Post(ctx context.Context, body []byte) error {
+	var firstErr error
+
+	for _, rawURL := range n.params.URLs {
+		err := n.deliver(ctx, rawURL, body)
+		if err == nil {
+			continue
+		}
+
+		if dlErr := n.writeDeadLetter(rawURL, err, body); dlErr != nil {
+			err = fmt.Errorf("%w; %v", err, dlErr)
+		}
+
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// deliver sends the body to the endpoint with retries.
+func (n *WebhookNotifier) deliver(ctx context.Context, rawURL string, body []byte) error {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			if err := sleepContext(ctx, backoffDelay(n.params.Backoff, attempt)); err != nil {
+				return &DeliveryError{URL: rawURL, Err: err}
+			}
+		}
+
+		statusCode, err := n.send(ctx, rawURL, body)
+		if err == nil && statusCode >= 200 && statusCode <= 299 {
+			return nil
+		}
+
+		if attempt >= n.params.MaxRetries || ctx.Err() != nil || (err == nil && !retryableStatus(statusCode)) {
+			return &DeliveryError{URL: rawURL, StatusCode: statusCode, Err: err}
+		}
+	}
+}
+
+// send makes a single signed request and returns the response status code.
+func (n *WebhookNotifier) send(ctx context.Context, rawURL string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	for key, values := range n.params.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	req.Header.Set("Content-Type", mediaType)
+	req.Header.Set("User-Agent", userAgent)
+
+	if len(n.params.Secret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(WebhookTimestampHeader, timestamp)
+		req.Header.Set(WebhookSignatureHeader, SignWebhook(n.params.Secret, timestamp, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if err := resp.Body.Close(); err != nil {
+		return resp.StatusCode, err
+	}
+
+	return resp.StatusCode, nil
+}
+
+// writeDeadLetter appends the undeliverable payload to the dead-letter file.
+func (n *WebhookNotifier) writeDeadLetter(rawURL string, deliveryErr error, body []byte) (err error) {
+	if n.params.DeadLetterPath == "" {
+		return nil
+	}
+
+	line, err := json.Marshal(deadLetter{
+		URL:      rawURL,
+		Error:    deliveryErr.Error(),
+		FailedAt: time.Now().UTC(),
+		Payload:  body,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot write dead letter: %w", err)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.params.DeadLetterPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("cannot write dead letter: %w", err)
+	}
+
+	defer func() {
+		if cerr := f.Close(); err == nil && cerr != nil {
+			err = fmt.Errorf("cannot write dead letter: %w", cerr)
+		}
+	}()
+
+	if _, err = f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("cannot write dead letter: %w", err)
+	}
+
+	return nil
+}
+
+// SignWebhook returns the signature of the timestamp and the body: "sha256=" followed by
+// the hex-encoded HMAC-SHA256 of the timestamp, a dot and the body.
+func SignWebhook(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook reports whether the signature of the received request is valid.
+// Receivers should also reject the timestamps too far from the current time.
+func VerifyWebhook(secret []byte, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(SignWebhook(secret, timestamp, body)), []byte(signature))
+}
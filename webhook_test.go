@@ -0,0 +1,114 @@
+package registrantalert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestWebhookNotifier tests the webhook delivery, signatures, retries and the dead-letter file.
+func TestWebhookNotifier(t *testing.T) {
+	secret := []byte("secret")
+
+	var calls int32
+	var payloads []WebhookPayload
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if !VerifyWebhook(secret, req.Header.Get(WebhookTimestampHeader), body, req.Header.Get(WebhookSignatureHeader)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch req.URL.Path {
+		case "/flaky":
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			var payload WebhookPayload
+			if err := json.Unmarshal(body, &payload); err != nil {
+				t.Error(err)
+			}
+			payloads = append(payloads, payload)
+		case "/rejecting":
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer receiver.Close()
+
+	deadLetterPath := filepath.Join(t.TempDir(), "dead-letter.ndjson")
+
+	notifier, err := NewWebhookNotifier(WebhookParams{
+		URLs:           []string{receiver.URL + "/flaky", receiver.URL + "/rejecting"},
+		Secret:         secret,
+		HTTPClient:     receiver.Client(),
+		MaxRetries:     2,
+		Backoff:        time.Millisecond,
+		DeadLetterPath: deadLetterPath,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := []SearchResult{
+		{
+			Search: Search{Name: "whois"},
+			RunAt:  time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC),
+			Response: &RegistrantAlertResponse{DomainsList: []DomainItem{
+				{"whoisdodster.com", Added, Time(time.Date(2022, 10, 30, 0, 0, 0, 0, time.UTC))},
+			}},
+		},
+		{
+			Search:   Search{Name: "empty"},
+			Response: &RegistrantAlertResponse{},
+		},
+	}
+
+	err = notifier.Notify(context.Background(), results...)
+
+	var deliveryErr *DeliveryError
+	if !errors.As(err, &deliveryErr) || deliveryErr.URL != receiver.URL+"/rejecting" || deliveryErr.StatusCode != 400 {
+		t.Errorf("Notify() error = %v, want delivery error for /rejecting", err)
+	}
+
+	if len(payloads) != 1 || payloads[0].SearchName != "whois" || payloads[0].Events[0].DomainName != "whoisdodster.com" {
+		t.Errorf("received payloads = %+v", payloads)
+	}
+	if calls != 3 {
+		t.Errorf("got %d calls to /flaky, want 3", calls)
+	}
+
+	deadLetters, err := os.ReadFile(deadLetterPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(deadLetters)), "\n")
+	if len(lines) != 1 || !strings.Contains(lines[0], `"url":"`+receiver.URL+`/rejecting"`) ||
+		!strings.Contains(lines[0], `"domainName":"whoisdodster.com"`) {
+		t.Errorf("dead letters = %s", deadLetters)
+	}
+}
+
+// TestNewWebhookNotifier tests the WebhookParams validation.
+func TestNewWebhookNotifier(t *testing.T) {
+	_, err := NewWebhookNotifier(WebhookParams{})
+	checkErr(t, err, `invalid argument: "WebhookParams.URLs" must have at least 1 item.`)
+
+	_, err = NewWebhookNotifier(WebhookParams{URLs: []string{"https://example.com/hook", "example.com"}})
+	checkErr(t, err, `invalid argument: "WebhookParams.URLs.1" must be an absolute http(s) URL.`)
+}